@@ -1,8 +1,6 @@
 package zabbix
 
-import (
-	"strconv"
-)
+import "context"
 
 type (
 	// Whether to pause escalation during maintenance periods or not.
@@ -137,12 +135,12 @@ type Action struct {
 	Period          string     `json:"esc_period,omitempty"`
 	EventSource     EventType  `json:"eventsource,omitempty"` // NOTE: Can not update
 	Name            string     `json:"name"`
-	DefaultMessage  string     `json:"def_longdata,omitempty"`  // NOTE: no support on Zabbix 5.0 onward
-	DefaultSubject  string     `json:"def_shortdata,omitempty"` // NOTE: no support on Zabbix 5.0 onward
-	RecoveryMessage string     `json:"r_longdata,omitempty"`    // NOTE: no support on Zabbix 5.0 onward
-	RecoverySubject string     `json:"r_shortdata,omitempty"`   // NOTE: no support on Zabbix 5.0 onward
-	AckMessage      string     `json:"ack_longdata,omitempty"`  // NOTE: no support on Zabbix 5.0 onward
-	AckSubject      string     `json:"ack_shortdata,omitempty"` // NOTE: no support on Zabbix 5.0 onward
+	DefaultMessage  string     `json:"def_longdata,omitempty"`  // NOTE: dropped for Zabbix 5.0 onward
+	DefaultSubject  string     `json:"def_shortdata,omitempty"` // NOTE: dropped for Zabbix 5.0 onward
+	RecoveryMessage string     `json:"r_longdata,omitempty"`    // NOTE: dropped for Zabbix 5.0 onward
+	RecoverySubject string     `json:"r_shortdata,omitempty"`   // NOTE: dropped for Zabbix 5.0 onward
+	AckMessage      string     `json:"ack_longdata,omitempty"`  // NOTE: dropped for Zabbix 5.0 onward
+	AckSubject      string     `json:"ack_shortdata,omitempty"` // NOTE: dropped for Zabbix 5.0 onward
 	Status          StatusType `json:"status,omitempty,string"`
 	PauseSuppressed *PauseType `json:"pause_suppressed,omitempty,string"`
 
@@ -304,15 +302,21 @@ type ActionUpdateOperations []ActionUpdateOperation
 // ActionsGet Wrapper for action.get
 // https://www.zabbix.com/documentation/4.0/manual/api/reference/action/get
 func (api *API) ActionsGet(params Params) (res Actions, err error) {
-	if _, present := params["output"]; !present {
-		params["output"] = "extend"
-	}
-	err = api.CallWithErrorParse("action.get", params, &res)
-	return
+	return api.ActionsGetContext(context.Background(), params)
+}
+
+// ActionsGetContext is like ActionsGet but uses ctx for the underlying requests.
+func (api *API) ActionsGetContext(ctx context.Context, params Params) (res Actions, err error) {
+	return NewRepository[Action](api).Get(ctx, params)
 }
 
 // ActionGetByID Gets action by Id only if there is exactly 1 matching action.
 func (api *API) ActionGetByID(id string) (res *Action, err error) {
+	return api.ActionGetByIDContext(context.Background(), id)
+}
+
+// ActionGetByIDContext is like ActionGetByID but uses ctx for the underlying requests.
+func (api *API) ActionGetByIDContext(ctx context.Context, id string) (res *Action, err error) {
 	params := Params{
 		"actionids":                id,
 		"selectFilter":             "extend",
@@ -320,7 +324,7 @@ func (api *API) ActionGetByID(id string) (res *Action, err error) {
 		"selectRecoveryOperations": "extend",
 		"selectUpdateOperations":   "extend",
 	}
-	actions, err := api.ActionsGet(params)
+	actions, err := api.ActionsGetContext(ctx, params)
 	if err != nil {
 		return
 	}
@@ -337,61 +341,44 @@ func (api *API) ActionGetByID(id string) (res *Action, err error) {
 // ActionsCreate Wrapper for action.create
 // https://www.zabbix.com/documentation/4.0/manual/api/reference/action/create
 func (api *API) ActionsCreate(actions Actions) (err error) {
-	response, err := api.CallWithError("action.create", actions)
-	if err != nil {
-		return
-	}
+	return api.ActionsCreateContext(context.Background(), actions)
+}
 
-	result := response.Result.(map[string]interface{})
-	actionids := result["actionids"].([]interface{})
-	for i, id := range actionids {
-		switch id.(type) {
-		case string:
-			actions[i].ActionID = id.(string)
-		case float64:
-			actions[i].ActionID = strconv.Itoa(int(id.(float64)))
-		}
-	}
-	return
+// ActionsCreateContext is like ActionsCreate but uses ctx for the underlying requests.
+func (api *API) ActionsCreateContext(ctx context.Context, actions Actions) (err error) {
+	return NewRepository[Action](api).Create(ctx, actions)
 }
 
 // ActionsUpdate Wrapper for action.update
 // https://www.zabbix.com/documentation/4.0/manual/api/reference/action/update
 func (api *API) ActionsUpdate(actions Actions) (err error) {
-	_, err = api.CallWithError("action.update", actions)
-	return
+	return api.ActionsUpdateContext(context.Background(), actions)
+}
+
+// ActionsUpdateContext is like ActionsUpdate but uses ctx for the underlying requests.
+func (api *API) ActionsUpdateContext(ctx context.Context, actions Actions) (err error) {
+	return NewRepository[Action](api).Update(ctx, actions)
 }
 
 // ActionsDelete Wrapper for action.delete
 // Cleans ActionId in all actions elements if call succeed.
 // https://www.zabbix.com/documentation/4.0/manual/api/reference/action/delete
 func (api *API) ActionsDelete(actions Actions) (err error) {
-	ids := make([]string, len(actions))
-	for i, action := range actions {
-		ids[i] = action.ActionID
-	}
+	return api.ActionsDeleteContext(context.Background(), actions)
+}
 
-	err = api.ActionsDeleteByIds(ids)
-	if err == nil {
-		for i := range actions {
-			actions[i].ActionID = ""
-		}
-	}
-	return
+// ActionsDeleteContext is like ActionsDelete but uses ctx for the underlying requests.
+func (api *API) ActionsDeleteContext(ctx context.Context, actions Actions) (err error) {
+	return NewRepository[Action](api).Delete(ctx, actions)
 }
 
 // ActionsDeleteByIds Wrapper for action.delete
 // https://www.zabbix.com/documentation/4.0/manual/api/reference/action/delete
 func (api *API) ActionsDeleteByIds(ids []string) (err error) {
-	response, err := api.CallWithError("action.delete", ids)
-	if err != nil {
-		return
-	}
+	return api.ActionsDeleteByIdsContext(context.Background(), ids)
+}
 
-	result := response.Result.(map[string]interface{})
-	actionids := result["actionids"].([]interface{})
-	if len(ids) != len(actionids) {
-		err = &ExpectedMore{len(ids), len(actionids)}
-	}
-	return
+// ActionsDeleteByIdsContext is like ActionsDeleteByIds but uses ctx for the underlying requests.
+func (api *API) ActionsDeleteByIdsContext(ctx context.Context, ids []string) (err error) {
+	return NewRepository[Action](api).DeleteByIDs(ctx, ids)
 }
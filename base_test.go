@@ -1,15 +1,23 @@
 package zabbix_test
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"math/rand"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"regexp"
 	"testing"
 	"time"
 
 	zapi "github.com/claranet/go-zabbix-api"
+	"github.com/claranet/go-zabbix-api/zabbixtest"
 	"github.com/hashicorp/go-version"
 )
 
@@ -28,8 +36,14 @@ func init() {
 	}
 	_host += "-testing"
 
+	// Without a Zabbix server, test against the fake one, reporting TEST_ZABBIX_VERSION
 	if os.Getenv("TEST_ZABBIX_URL") == "" {
-		log.Fatal("Set environment variables TEST_ZABBIX_URL (and optionally TEST_ZABBIX_USER and TEST_ZABBIX_PASSWORD)")
+		srv := zabbixtest.NewServer(zabbixtest.WithVersion(os.Getenv("TEST_ZABBIX_VERSION")))
+		os.Setenv("TEST_ZABBIX_URL", srv.URL)
+		if os.Getenv("TEST_ZABBIX_USER") == "" {
+			os.Setenv("TEST_ZABBIX_USER", zabbixtest.AdminUser)
+			os.Setenv("TEST_ZABBIX_PASSWORD", zabbixtest.AdminPassword)
+		}
 	}
 }
 
@@ -123,3 +137,97 @@ func TestVersion(t *testing.T) {
 		t.Errorf("Unexpected version: %s", v)
 	}
 }
+
+// testRPCMethod returns the JSON-RPC method of r, restoring its body for later reads.
+func testRPCMethod(r *http.Request) string {
+	b, _ := io.ReadAll(r.Body)
+	r.Body = io.NopCloser(bytes.NewReader(b))
+	var req struct {
+		Method string `json:"method"`
+	}
+	json.Unmarshal(b, &req)
+	return req.Method
+}
+
+// testDecodeRequest decodes the JSON-RPC request r into v.
+func testDecodeRequest(r *http.Request, v interface{}) {
+	json.NewDecoder(r.Body).Decode(v)
+}
+
+// testFakeAPI returns an API connected to a local server which answers the version
+// request with version and hands every other request to handler.
+func testFakeAPI(t *testing.T, version string, handler http.HandlerFunc) *zapi.API {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if testRPCMethod(r) == "APIInfo.version" {
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","result":%q,"id":1}`, version)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	api, err := zapi.NewAPI(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return api
+}
+
+func TestCallContextCanceled(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := api.HostsGetContext(ctx, zapi.Params{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	var apiErr *zapi.Error
+	if errors.As(err, &apiErr) {
+		t.Errorf("Context error must not be an API error: %#v", apiErr)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = api.CallContext(ctx, "host.get", zapi.Params{})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestTokenAuth(t *testing.T) {
+	for _, tc := range []struct {
+		version string
+		header  string
+		auth    string
+	}{
+		{"6.0.0", "", "secret"},
+		{"6.4.0", "Bearer secret", ""},
+		{"7.0.5", "Bearer secret", ""},
+	} {
+		var header, auth string
+		api := testFakeAPI(t, tc.version, func(w http.ResponseWriter, r *http.Request) {
+			var req struct {
+				Auth string `json:"auth"`
+			}
+			json.NewDecoder(r.Body).Decode(&req)
+			header, auth = r.Header.Get("Authorization"), req.Auth
+			w.Write([]byte(`{"jsonrpc":"2.0","result":[],"id":1}`))
+		})
+		api.SetToken("secret")
+
+		if _, err := api.HostsGet(zapi.Params{}); err != nil {
+			t.Fatal(err)
+		}
+		if header != tc.header || auth != tc.auth {
+			t.Errorf("Zabbix %s: expected header %q and auth %q, got %q and %q", tc.version, tc.header, tc.auth, header, auth)
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package zabbix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Batch groups several API calls into a single JSON-RPC 2.0 batch request,
+// saving a round-trip per call. Build it with API.NewBatch and Add, then send it with Do.
+//
+//	var hosts zabbix.Hosts
+//	var items zabbix.Items
+//	b := api.NewBatch()
+//	b.Add("host.get", zabbix.Params{"output": "extend"}, &hosts)
+//	itemCall := b.Add("item.get", zabbix.Params{"output": "extend"}, &items)
+//	err := b.Do()
+//	// err is a transport error, itemCall.Err is the error of item.get
+type Batch struct {
+	api   *API
+	calls []*BatchCall
+}
+
+// BatchCall is one call of a Batch.
+type BatchCall struct {
+	Method string
+	Params interface{}
+	// Err is the error of this call, set by Batch.Do. It is an *Error for API errors.
+	Err error
+
+	result interface{}
+	id     int32
+}
+
+// NewBatch Creates an empty batch of calls sent with api.
+func (api *API) NewBatch() *Batch {
+	return &Batch{api: api}
+}
+
+// Add Adds a call of method to the batch. After Do, the result of the call is parsed
+// into result, unless it is nil, and its error is stored in the returned BatchCall.
+func (b *Batch) Add(method string, params interface{}, result interface{}) *BatchCall {
+	call := &BatchCall{Method: method, Params: params, result: result}
+	b.calls = append(b.calls, call)
+	return call
+}
+
+// Calls returns the calls added to the batch, in order.
+func (b *Batch) Calls() []*BatchCall {
+	return b.calls
+}
+
+// Do Sends all calls of the batch in one request and matches responses to calls by id.
+// Each call goes through the interceptors first, see API.Use.
+// err is something network or marshaling related, errors of individual calls are stored in their Err field.
+func (b *Batch) Do() (err error) {
+	return b.DoContext(context.Background())
+}
+
+// DoContext is like Do but uses ctx for the HTTP request.
+func (b *Batch) DoContext(ctx context.Context) (err error) {
+	if len(b.calls) == 0 {
+		return
+	}
+	api := b.api
+
+	// Each call runs through the interceptors under its own span, the batch request under the one of ctx
+	ctxs := make([]context.Context, len(b.calls))
+	spans := make([]Span, len(b.calls))
+	for i, call := range b.calls {
+		ctxs[i], spans[i] = api.startSpan(ctx, call.Method, call.Params)
+	}
+	defer func() {
+		for i, call := range b.calls {
+			if spans[i] != nil {
+				spans[i].SetAttribute(AttrRequestID, call.id)
+			}
+			if err != nil {
+				endSpan(spans[i], nil, err)
+			} else {
+				endSpan(spans[i], nil, call.Err)
+			}
+		}
+	}()
+
+	// Each call goes through the interceptors on its own, the calls reaching the innermost
+	// CallFunc are gathered by r and sent together.
+	r := &batchRound{open: true, seen: make([]bool, len(b.calls)), ready: make(chan struct{}), arrived: make(chan struct{}, 1)}
+	responses := make([][]byte, len(b.calls))
+	errs := make([]error, len(b.calls))
+	var wg sync.WaitGroup
+	for i, call := range b.calls {
+		wg.Add(1)
+		go func(i int, call *BatchCall) {
+			defer wg.Done()
+			responses[i], errs[i] = api.chain(func(ctx context.Context, method string, params interface{}) ([]byte, error) {
+				return r.wait(ctx, api, i, method, params)
+			})(ctxs[i], call.Method, call.Params)
+			r.mark(i)
+		}(i, call)
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	r.gather(ctx)
+	r.mu.Lock()
+	r.open = false
+	pending := r.pending
+	r.mu.Unlock()
+	sort.Slice(pending, func(i, j int) bool { return pending[i].index < pending[j].index })
+	err = b.sendPending(ctx, pending)
+	select {
+	case <-done:
+	case <-ctx.Done():
+		// an interceptor may still be running, its call is abandoned
+		if err == nil {
+			err = ctx.Err()
+		}
+	}
+	if err != nil {
+		return
+	}
+
+	for i, call := range b.calls {
+		call.Err = errs[i]
+		if call.Err != nil {
+			continue
+		}
+		var response RawResponse
+		switch {
+		case json.Unmarshal(responses[i], &response) != nil:
+			call.Err = fmt.Errorf("zabbix: invalid %s response: %.100s", call.Method, responses[i])
+		case response.Error != nil:
+			call.Err = response.Error
+		case call.result != nil:
+			call.Err = json.Unmarshal(response.Result, call.result)
+		}
+	}
+	return
+}
+
+// batchGatherDelay is how long a batch waits for its calls still in the interceptors
+// once no other call reached the innermost CallFunc. An interceptor may hold a call
+// until another one returns, like singleflight, so the batch cannot wait for all of them.
+const batchGatherDelay = 10 * time.Millisecond
+
+// batchRound gathers the calls of a Batch reaching the innermost CallFunc of the interceptors.
+type batchRound struct {
+	mu      sync.Mutex
+	open    bool // calls are still gathered
+	pending []*pendingCall
+	seen    []bool // the call at index reached the innermost CallFunc or returned
+	count   int
+	ready   chan struct{} // closed once every call was seen
+	arrived chan struct{} // signaled when a call reaches the innermost CallFunc
+}
+
+// pendingCall is a call of a batchRound, waiting for its response.
+type pendingCall struct {
+	index    int
+	method   string
+	params   interface{}
+	id       int32
+	response chan []byte
+	err      error
+}
+
+// markLocked records that the call at index was seen, under r.mu.
+func (r *batchRound) markLocked(index int) {
+	if !r.seen[index] {
+		r.seen[index] = true
+		r.count++
+		if r.count == len(r.seen) {
+			close(r.ready)
+		}
+	}
+}
+
+// mark records that the chain of the call at index returned.
+func (r *batchRound) mark(index int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.markLocked(index)
+}
+
+// gather returns once every call was seen, once no call reached the innermost CallFunc
+// for batchGatherDelay after the last one did, or when ctx is done.
+func (r *batchRound) gather(ctx context.Context) {
+	var idle <-chan time.Time
+	for {
+		select {
+		case <-r.ready:
+			return
+		case <-r.arrived:
+			idle = time.After(batchGatherDelay)
+		case <-idle:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// wait adds the call to the batch and returns its response once the batch was sent.
+// Calls reaching it once the batch was sent, like calls made again by an interceptor, are sent alone.
+func (r *batchRound) wait(ctx context.Context, api *API, index int, method string, params interface{}) ([]byte, error) {
+	r.mu.Lock()
+	if !r.open {
+		r.mu.Unlock()
+		return api.do(ctx, method, params)
+	}
+	call := &pendingCall{index: index, method: method, params: params, response: make(chan []byte, 1)}
+	r.pending = append(r.pending, call)
+	r.markLocked(index)
+	r.mu.Unlock()
+	select {
+	case r.arrived <- struct{}{}:
+	default:
+	}
+
+	select {
+	case response := <-call.response:
+		return response, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// sendPending sends pending calls in one request, renewing the session like API.do,
+// and hands each call its response.
+func (b *Batch) sendPending(ctx context.Context, pending []*pendingCall) (err error) {
+	defer func() {
+		for _, call := range pending {
+			b.calls[call.index].id = call.id
+			if err != nil {
+				call.err = err
+			}
+			close(call.response)
+		}
+	}()
+	if len(pending) == 0 {
+		return
+	}
+	if err = ctx.Err(); err != nil {
+		return
+	}
+	api := b.api
+
+	auth := api.authToken()
+	if auth != "" {
+		if err = api.ensureServerVersion(ctx); err != nil {
+			return
+		}
+	}
+	responses, err := b.send(ctx, pending, auth)
+	if err != nil {
+		return
+	}
+	if auth != "" && api.credentialSource() != nil && allSessionExpired(responses) {
+		if err = api.relogin(ctx, auth); err != nil {
+			return
+		}
+		if responses, err = b.send(ctx, pending, api.authToken()); err != nil {
+			return
+		}
+	}
+
+	byID := make(map[int32]*RawResponse, len(responses))
+	for i := range responses {
+		byID[responses[i].ID] = &responses[i]
+	}
+	for _, call := range pending {
+		response, ok := byID[call.id]
+		if !ok {
+			call.err = fmt.Errorf("no response to %s call with id %d", call.method, call.id)
+			continue
+		}
+		raw, err := json.Marshal(response)
+		if err != nil {
+			call.err = err
+			continue
+		}
+		call.response <- raw
+	}
+	return nil
+}
+
+// send posts the pending calls authenticated with auth and decodes the responses.
+func (b *Batch) send(ctx context.Context, pending []*pendingCall, auth string) (responses []RawResponse, err error) {
+	api := b.api
+	auth, bearer := api.authFields(auth)
+
+	ex := &exchange{bearer: bearer}
+	requests := make([]request, len(pending))
+	for i, call := range pending {
+		call.id = atomic.AddInt32(&api.id, 1)
+		requests[i] = request{"2.0", call.method, call.params, auth, call.id}
+		ex.methods = append(ex.methods, call.method)
+		ex.ids = append(ex.ids, call.id)
+	}
+	ex.body, err = json.Marshal(requests)
+	if err != nil {
+		return
+	}
+
+	res, err := api.postRetry(ctx, ex)
+	if err != nil {
+		return
+	}
+
+	// Zabbix answers with a single error object when the batch itself is invalid.
+	if res = bytes.TrimSpace(res); len(res) > 0 && res[0] == '{' {
+		var response RawResponse
+		if err = json.Unmarshal(res, &response); err != nil {
+			return
+		}
+		if response.Error != nil {
+			return nil, response.Error
+		}
+		return []RawResponse{response}, nil
+	}
+	err = json.Unmarshal(res, &responses)
+	return
+}
+
+// allSessionExpired reports whether every response failed because the session expired.
+func allSessionExpired(responses []RawResponse) bool {
+	for _, response := range responses {
+		if !errors.Is(response.Error, ErrSessionExpired) {
+			return false
+		}
+	}
+	return len(responses) > 0
+}
@@ -0,0 +1,59 @@
+package zabbix_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	zapi "github.com/claranet/go-zabbix-api"
+)
+
+func TestBatch(t *testing.T) {
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		var requests []struct {
+			Method string `json:"method"`
+			ID     int32  `json:"id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&requests); err != nil {
+			t.Errorf("Expected a batch request: %s", err)
+		}
+		w.Write([]byte("["))
+		// Answer in reverse order to check matching by id
+		for i := len(requests) - 1; i >= 0; i-- {
+			req := requests[i]
+			switch req.Method {
+			case "host.get":
+				fmt.Fprintf(w, `{"jsonrpc":"2.0","result":[{"hostid":"10084","host":"Zabbix server"}],"id":%d}`, req.ID)
+			case "item.get":
+				fmt.Fprintf(w, `{"jsonrpc":"2.0","result":[{"itemid":"1"},{"itemid":"2"}],"id":%d}`, req.ID)
+			default:
+				fmt.Fprintf(w, `{"jsonrpc":"2.0","error":{"code":-32601,"message":"Method not found.","data":"Incorrect API \"%s\"."},"id":%d}`, req.Method, req.ID)
+			}
+			if i > 0 {
+				w.Write([]byte(","))
+			}
+		}
+		w.Write([]byte("]"))
+	})
+
+	var hosts zapi.Hosts
+	var items zapi.Items
+	b := api.NewBatch()
+	hostCall := b.Add("host.get", zapi.Params{"output": "extend"}, &hosts)
+	itemCall := b.Add("item.get", zapi.Params{"output": "extend"}, &items)
+	badCall := b.Add("foo.get", zapi.Params{}, nil)
+	if err := b.Do(); err != nil {
+		t.Fatal(err)
+	}
+
+	if hostCall.Err != nil || len(hosts) != 1 || hosts[0].HostID != "10084" {
+		t.Errorf("Bad host.get result: %v %#v", hostCall.Err, hosts)
+	}
+	if itemCall.Err != nil || len(items) != 2 {
+		t.Errorf("Bad item.get result: %v %#v", itemCall.Err, items)
+	}
+	if e, ok := badCall.Err.(*zapi.Error); !ok || e.Code != -32601 {
+		t.Errorf("Expected API error, got %v", badCall.Err)
+	}
+}
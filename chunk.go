@@ -0,0 +1,192 @@
+package zabbix
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultChunkSize is the default API.ChunkSize, small enough for the default
+// PHP post_max_size, memory_limit and max_execution_time of the frontend.
+const DefaultChunkSize = 1000
+
+// ChunkError is returned when a chunk of a bulk operation failed after Done of
+// the Total objects or IDs were processed by the previous chunks.
+type ChunkError struct {
+	Done  int
+	Total int
+	Err   error
+}
+
+func (e *ChunkError) Error() string {
+	return fmt.Sprintf("zabbix: %d of %d objects processed: %v", e.Done, e.Total, e.Err)
+}
+
+func (e *ChunkError) Unwrap() error {
+	return e.Err
+}
+
+// eachChunk calls f with the bounds of consecutive chunks of n elements, in order,
+// and stops at the first error. When the elements span several chunks,
+// the error is returned as a *ChunkError.
+func (api *API) eachChunk(n int, f func(lo, hi int) error) error {
+	if !api.chunked(n) {
+		return f(0, n)
+	}
+	size := api.ChunkSize
+
+	for lo := 0; lo < n; lo += size {
+		hi := lo + size
+		if hi > n {
+			hi = n
+		}
+		if err := f(lo, hi); err != nil {
+			return &ChunkError{Done: lo, Total: n, Err: err}
+		}
+	}
+	return nil
+}
+
+// chunked reports whether eachChunk splits n elements in several chunks.
+func (api *API) chunked(n int) bool {
+	return api.ChunkSize > 0 && n > api.ChunkSize
+}
+
+// chunkOrder is the order and limit asked by the params of a get, applied on the client
+// to the merged results of several requests, as the server applies them to each request.
+type chunkOrder struct {
+	fields [][]int // indexes of the sort fields
+	desc   []bool
+	limit  int // 0 for no limit
+}
+
+// newChunkOrder returns the order of params, whose sortfield must be one of fields,
+// the indexes of the fields of the objects by name.
+func newChunkOrder(prefix string, params Params, fields map[string][]int) (o chunkOrder, err error) {
+	if v, present := params["limit"]; present {
+		if o.limit, err = strconv.Atoi(fmt.Sprint(v)); err != nil || o.limit < 0 {
+			return o, fmt.Errorf("%w: %s limit: invalid limit %v", ErrInvalidParams, prefix, v)
+		}
+	}
+	names, ok := sortList(params["sortfield"])
+	if !ok {
+		return o, fmt.Errorf("%w: %s sortfield: invalid sort fields %v", ErrInvalidParams, prefix, params["sortfield"])
+	}
+	orders, ok := sortList(params["sortorder"])
+	if !ok || len(orders) > 1 && len(orders) != len(names) {
+		return o, fmt.Errorf("%w: %s sortorder: invalid sort orders %v", ErrInvalidParams, prefix, params["sortorder"])
+	}
+	for i, name := range names {
+		index, ok := fields[name]
+		if !ok {
+			return o, fmt.Errorf("%w: %s sortfield: %q cannot be sorted across chunks", ErrInvalidParams, prefix, name)
+		}
+		order := ""
+		if len(orders) == 1 {
+			order = orders[0]
+		} else if len(orders) > 1 {
+			order = orders[i]
+		}
+		o.fields = append(o.fields, index)
+		o.desc = append(o.desc, strings.EqualFold(order, string(SortDesc)))
+	}
+	return o, nil
+}
+
+// mergeChunks sorts the merged results of several requests in the order of o and cuts them to its limit.
+// Numbers, including numeric strings, are compared by value and other strings byte per byte.
+func mergeChunks[T any](o chunkOrder, res []T) []T {
+	if len(o.fields) > 0 {
+		sort.SliceStable(res, func(i, j int) bool {
+			a, b := reflect.ValueOf(&res[i]).Elem(), reflect.ValueOf(&res[j]).Elem()
+			for k, index := range o.fields {
+				c := compareValues(a.FieldByIndex(index), b.FieldByIndex(index))
+				if o.desc[k] {
+					c = -c
+				}
+				if c != 0 {
+					return c < 0
+				}
+			}
+			return false
+		})
+	}
+	if o.limit > 0 && len(res) > o.limit {
+		res = res[:o.limit]
+	}
+	return res
+}
+
+// compareValues compares two values of the same field, like cmp.Compare.
+func compareValues(a, b reflect.Value) int {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return cmp.Compare(a.Uint(), b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(a.Float(), b.Float())
+	case reflect.String:
+		x, y := a.String(), b.String()
+		if i, err := strconv.ParseInt(x, 10, 64); err == nil {
+			if j, err := strconv.ParseInt(y, 10, 64); err == nil {
+				return cmp.Compare(i, j)
+			}
+		}
+		if f, err := strconv.ParseFloat(x, 64); err == nil {
+			if g, err := strconv.ParseFloat(y, 64); err == nil {
+				return cmp.Compare(f, g)
+			}
+		}
+		return strings.Compare(x, y)
+	}
+	if t, ok := a.Interface().(time.Time); ok {
+		return t.Compare(b.Interface().(time.Time))
+	}
+	return strings.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+}
+
+// sortList returns the strings of a sortfield or sortorder parameter, false for other types.
+func sortList(v interface{}) ([]string, bool) {
+	switch v := v.(type) {
+	case nil:
+		return nil, true
+	case SortOrder:
+		return []string{string(v)}, true
+	case []SortOrder:
+		list := make([]string, len(v))
+		for i, order := range v {
+			list[i] = string(order)
+		}
+		return list, true
+	}
+	return stringList(v)
+}
+
+// chunkDone returns the number of elements processed before err, n on success.
+func chunkDone(n int, err error) int {
+	if err == nil {
+		return n
+	}
+	if e, ok := err.(*ChunkError); ok {
+		return e.Done
+	}
+	return 0
+}
+
+// idsParam returns the IDs of params[name] as a slice value, if there are more than one.
+func idsParam(params Params, name string) (ids reflect.Value, ok bool) {
+	v, present := params[name]
+	if !present || v == nil {
+		return
+	}
+	ids = reflect.ValueOf(v)
+	if ids.Kind() != reflect.Slice && ids.Kind() != reflect.Array {
+		return
+	}
+	return ids, ids.Len() > 1
+}
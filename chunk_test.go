@@ -0,0 +1,169 @@
+package zabbix_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+
+	zapi "github.com/claranet/go-zabbix-api"
+)
+
+// testChunkAPI returns an API with a chunk size of 2 and the sizes of the requests it received.
+// item.create fails when it receives an item named "fail".
+func testChunkAPI(t *testing.T) (*zapi.API, *[]int) {
+	var sizes []int
+	next := 0
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		testDecodeRequest(r, &req)
+
+		var result interface{}
+		switch req.Method {
+		case "item.create":
+			var items []zapi.Item
+			json.Unmarshal(req.Params, &items)
+			sizes = append(sizes, len(items))
+			ids := []string{}
+			for _, item := range items {
+				if item.Name == "fail" {
+					fmt.Fprint(w, `{"jsonrpc":"2.0","error":{"code":-32500,"message":"Application error.","data":"No memory."},"id":1}`)
+					return
+				}
+				next++
+				ids = append(ids, strconv.Itoa(next))
+			}
+			result = map[string]interface{}{"itemids": ids}
+		case "item.get":
+			var params struct {
+				ItemIDs   []string `json:"itemids"`
+				SortOrder string   `json:"sortorder"`
+				Limit     int      `json:"limit"`
+			}
+			json.Unmarshal(req.Params, &params)
+			sizes = append(sizes, len(params.ItemIDs))
+			items := []zapi.Item{}
+			for _, id := range params.ItemIDs {
+				items = append(items, zapi.Item{ItemID: id, Name: "item " + id})
+			}
+			// the IDs are sent sorted, so items are sorted by ID or name
+			if params.SortOrder == "DESC" {
+				for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+					items[i], items[j] = items[j], items[i]
+				}
+			}
+			if params.Limit > 0 && len(items) > params.Limit {
+				items = items[:params.Limit]
+			}
+			result = items
+		case "item.delete":
+			var ids []string
+			json.Unmarshal(req.Params, &ids)
+			sizes = append(sizes, len(ids))
+			result = map[string]interface{}{"itemids": ids}
+		}
+		b, _ := json.Marshal(result)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","result":%s,"id":1}`, b)
+	})
+	api.ChunkSize = 2
+	return api, &sizes
+}
+
+func TestChunkedCreateGetDelete(t *testing.T) {
+	api, sizes := testChunkAPI(t)
+	ctx := context.Background()
+
+	items := make(zapi.Items, 5)
+	if err := api.ItemsCreateContext(ctx, items); err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ItemID
+	}
+	if want := []string{"1", "2", "3", "4", "5"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("created IDs = %v, want %v", ids, want)
+	}
+
+	got, err := api.ItemsGetContext(ctx, zapi.Params{"itemids": ids})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 5 || got[0].ItemID != "1" || got[4].ItemID != "5" {
+		t.Errorf("ItemsGet = %v", got)
+	}
+
+	if err := api.ItemsDeleteContext(ctx, items); err != nil {
+		t.Fatal(err)
+	}
+	if items[4].ItemID != "" {
+		t.Errorf("ItemsDelete did not clean IDs: %v", items)
+	}
+
+	if want := []int{2, 2, 1, 2, 2, 1, 2, 2, 1}; !reflect.DeepEqual(*sizes, want) {
+		t.Errorf("request sizes = %v, want %v", *sizes, want)
+	}
+}
+
+func TestChunkedCreatePartial(t *testing.T) {
+	api, _ := testChunkAPI(t)
+
+	items := zapi.Items{{}, {}, {Name: "fail"}}
+	err := api.ItemsCreateContext(context.Background(), items)
+
+	var chunkErr *zapi.ChunkError
+	if !errors.As(err, &chunkErr) {
+		t.Fatalf("expected *ChunkError, got %v", err)
+	}
+	if chunkErr.Done != 2 || chunkErr.Total != 3 {
+		t.Errorf("ChunkError = %+v, want 2 of 3 done", chunkErr)
+	}
+	var apiErr *zapi.Error
+	if !errors.As(err, &apiErr) {
+		t.Errorf("expected wrapped *Error, got %v", err)
+	}
+	if items[0].ItemID != "1" || items[1].ItemID != "2" || items[2].ItemID != "" {
+		t.Errorf("created IDs are not filled in: %v", items)
+	}
+}
+
+func TestChunkedGetSorted(t *testing.T) {
+	api, sizes := testChunkAPI(t)
+
+	ids := []string{"1", "2", "3", "4", "5"}
+	items, err := api.ItemsGet(zapi.Params{"itemids": ids, "sortfield": "itemid", "sortorder": zapi.SortDesc, "limit": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, item := range items {
+		got = append(got, item.ItemID)
+	}
+	if want := []string{"5", "4", "3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the chunks sorted and limited, got %v", got)
+	}
+	if want := []int{2, 2, 1}; !reflect.DeepEqual(*sizes, want) {
+		t.Errorf("request sizes = %v, want %v", *sizes, want)
+	}
+
+	items, err = api.ItemsGet(zapi.Params{"itemids": ids, "sortfield": []string{"name"}, "sortorder": []zapi.SortOrder{zapi.SortAsc}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 5 || items[0].Name != "item 1" || items[4].Name != "item 5" {
+		t.Errorf("Expected the chunks sorted by name, got %v", items)
+	}
+
+	*sizes = nil
+	_, err = api.ItemsGet(zapi.Params{"itemids": ids, "sortfield": "unknown"})
+	if !errors.Is(err, zapi.ErrInvalidParams) || len(*sizes) != 0 {
+		t.Errorf("Expected ErrInvalidParams before any request, got %v and request sizes %v", err, *sizes)
+	}
+}
@@ -0,0 +1,90 @@
+package zabbix_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	zapi "github.com/claranet/go-zabbix-api"
+)
+
+// Run these tests with -race to check API is safe for concurrent use.
+
+func TestConcurrentCallsWithRelogin(t *testing.T) {
+	srv := &testSessionServer{}
+	api := testFakeAPI(t, "6.0.0", srv.handle)
+	if _, err := api.LoginWithCredentials(zapi.StaticCredentials{User: "Admin", Password: "zabbix"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			for j := 0; j < 5; j++ {
+				var err error
+				switch {
+				case i == 0 && j == 2:
+					srv.expire()
+				case i%50 == 1:
+					_, err = api.Version()
+				default:
+					_, err = api.HostsGet(zapi.Params{})
+				}
+				if err != nil {
+					t.Error(err)
+				}
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.logins != 2 {
+		t.Errorf("Expected exactly one re-login, got %d logins", srv.logins)
+	}
+}
+
+func TestConcurrentLazyVersionDetection(t *testing.T) {
+	var versionRequests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if testRPCMethod(r) == "APIInfo.version" {
+			atomic.AddInt32(&versionRequests, 1)
+			w.Write([]byte(`{"jsonrpc":"2.0","result":"7.0.0","id":1}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("Expected bearer auth, got %q", r.Header.Get("Authorization"))
+		}
+		w.Write([]byte(`{"jsonrpc":"2.0","result":[],"id":1}`))
+	}))
+	defer srv.Close()
+
+	api, err := zapi.NewAPIWithOptions(srv.URL, zapi.WithLazyVersionDetection(), zapi.WithToken("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := api.ItemsGet(zapi.Params{}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if versionRequests != 1 {
+		t.Errorf("Expected one version request, got %d", versionRequests)
+	}
+}
@@ -0,0 +1,84 @@
+package zabbix
+
+import (
+	"errors"
+	"strings"
+)
+
+// Sentinel errors classifying API errors, to be used with errors.Is:
+//
+//	host, err := api.HostGetByID(id)
+//	if errors.Is(err, zabbix.ErrNotFound) {
+//		// the host is gone
+//	}
+//
+// An *Error matches at most one of them, derived from its code and from the messages used by Zabbix versions.
+var (
+	// ErrNotFound is matched by errors about missing objects, and by ExpectedOneResult when nothing was found.
+	// Zabbix reports referred objects the user may not access exactly like missing ones
+	// ("No permissions to referred object or it does not exist!"), such errors match ErrNotFound.
+	ErrNotFound = errors.New("zabbix: object not found")
+	// ErrAlreadyExists is matched by errors about objects which already exist, like duplicate host names.
+	ErrAlreadyExists = errors.New("zabbix: object already exists")
+	// ErrPermissionDenied is matched by errors about methods or operations the user may not perform.
+	ErrPermissionDenied = errors.New("zabbix: permission denied")
+	// ErrSessionExpired is matched by errors about terminated or unknown sessions.
+	ErrSessionExpired = errors.New("zabbix: session expired")
+	// ErrInvalidParams is matched by other invalid params and invalid request errors.
+	ErrInvalidParams = errors.New("zabbix: invalid parameters")
+	// ErrVersionUnsupported is matched by errors about API methods the server does not know.
+	ErrVersionUnsupported = errors.New("zabbix: not supported by the server version")
+)
+
+// JSON-RPC error codes returned by Zabbix.
+const (
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Is makes errors.Is match e with the sentinel error classifying it.
+func (e *Error) Is(target error) bool {
+	if e == nil {
+		return false
+	}
+	class := e.class()
+	return class != nil && class == target
+}
+
+// class returns the sentinel error classifying e, or nil if none applies.
+func (e *Error) class() error {
+	data := strings.ToLower(e.Data)
+	switch {
+	case e.Code == codeMethodNotFound,
+		strings.Contains(data, "incorrect api"),
+		strings.Contains(data, "incorrect method"):
+		return ErrVersionUnsupported
+	case isSessionExpired(e):
+		return ErrSessionExpired
+	case strings.Contains(data, "already exists"):
+		return ErrAlreadyExists
+	case strings.Contains(data, "does not exist"),
+		strings.Contains(data, "no permissions to referred object"):
+		return ErrNotFound
+	case strings.Contains(data, "permission"):
+		return ErrPermissionDenied
+	case e.Code == codeInvalidParams, e.Code == codeInvalidRequest:
+		return ErrInvalidParams
+	}
+	return nil
+}
+
+// isSessionExpired reports whether e is the error Zabbix returns for an expired or unknown session:
+// "Session terminated, re-login, please." before 6.0, "Not authorized." or "Not authorised." later.
+func isSessionExpired(e *Error) bool {
+	data := strings.ToLower(e.Data)
+	return strings.Contains(data, "session terminated") ||
+		strings.Contains(data, "not authorised") ||
+		strings.Contains(data, "not authorized")
+}
+
+// Is makes errors.Is match an empty result with ErrNotFound.
+func (e *ExpectedOneResult) Is(target error) bool {
+	return *e == 0 && target == ErrNotFound
+}
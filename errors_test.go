@@ -0,0 +1,52 @@
+package zabbix_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	zapi "github.com/claranet/go-zabbix-api"
+)
+
+func TestErrorClasses(t *testing.T) {
+	sentinels := []error{
+		zapi.ErrNotFound,
+		zapi.ErrAlreadyExists,
+		zapi.ErrPermissionDenied,
+		zapi.ErrSessionExpired,
+		zapi.ErrInvalidParams,
+		zapi.ErrVersionUnsupported,
+	}
+	for _, tc := range []struct {
+		err      *zapi.Error
+		expected error
+	}{
+		{&zapi.Error{Code: -32602, Message: "Invalid params.", Data: "No permissions to referred object or it does not exist!"}, zapi.ErrNotFound},
+		{&zapi.Error{Code: -32500, Message: "Application error.", Data: "Object does not exist, or you have no permissions to it."}, zapi.ErrNotFound},
+		{&zapi.Error{Code: -32602, Message: "Invalid params.", Data: `Host with the same name "foo" already exists.`}, zapi.ErrAlreadyExists},
+		{&zapi.Error{Code: -32500, Message: "Application error.", Data: "No permissions to call \"host.create\"."}, zapi.ErrPermissionDenied},
+		{&zapi.Error{Code: -32602, Message: "Invalid params.", Data: "You do not have permission to perform this operation."}, zapi.ErrPermissionDenied},
+		{&zapi.Error{Code: -32602, Message: "Invalid params.", Data: "Session terminated, re-login, please."}, zapi.ErrSessionExpired},
+		{&zapi.Error{Code: -32500, Message: "Application error.", Data: "Not authorized."}, zapi.ErrSessionExpired},
+		{&zapi.Error{Code: -32602, Message: "Invalid params.", Data: `Invalid parameter "/1": unexpected parameter "foo".`}, zapi.ErrInvalidParams},
+		{&zapi.Error{Code: -32601, Message: "Method not found.", Data: `Incorrect API "application".`}, zapi.ErrVersionUnsupported},
+		{&zapi.Error{Code: -32500, Message: "Application error.", Data: "SQL statement execution has failed."}, nil},
+	} {
+		err := fmt.Errorf("wrapped: %w", tc.err)
+		for _, sentinel := range sentinels {
+			if errors.Is(err, sentinel) != (sentinel == tc.expected) {
+				t.Errorf("%s: errors.Is(%v) = %v", tc.err.Data, sentinel, !(sentinel == tc.expected))
+			}
+		}
+	}
+}
+
+func TestExpectedOneResultNotFound(t *testing.T) {
+	none, many := zapi.ExpectedOneResult(0), zapi.ExpectedOneResult(2)
+	if !errors.Is(&none, zapi.ErrNotFound) {
+		t.Error("Expected 0 results to match ErrNotFound")
+	}
+	if errors.Is(&many, zapi.ErrNotFound) {
+		t.Error("Expected 2 results not to match ErrNotFound")
+	}
+}
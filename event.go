@@ -1,9 +1,25 @@
 package zabbix
 
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
 type (
 	// Type of the event.
 	// "source" in https://www.zabbix.com/documentation/3.2/manual/api/reference/event/object#event
 	EventType string
+
+	// Type of the object related to the event.
+	// "object" in https://www.zabbix.com/documentation/current/manual/api/reference/event/object
+	EventObjectType int
+
+	// AcknowledgeAction is a bitmask of the actions of an event update.
+	// "action" in https://www.zabbix.com/documentation/current/manual/api/reference/event/acknowledge
+	AcknowledgeAction int
 )
 
 const (
@@ -16,3 +32,280 @@ const (
 	// internal event
 	InternalEvent EventType = "3"
 )
+
+const (
+	TriggerObject            EventObjectType = 0
+	DiscoveredHostObject     EventObjectType = 1
+	DiscoveredServiceObject  EventObjectType = 2
+	AutoRegisteredHostObject EventObjectType = 3
+	ItemObject               EventObjectType = 4
+	LLDRuleObject            EventObjectType = 5
+	ServiceObject            EventObjectType = 6
+)
+
+const (
+	// AcknowledgeClose closes the problem
+	AcknowledgeClose AcknowledgeAction = 1
+	// AcknowledgeAck acknowledges the event
+	AcknowledgeAck AcknowledgeAction = 2
+	// AcknowledgeMessage adds the message
+	AcknowledgeMessage AcknowledgeAction = 4
+	// AcknowledgeSeverity changes the severity
+	AcknowledgeSeverity AcknowledgeAction = 8
+	// AcknowledgeUnack unacknowledges the event
+	AcknowledgeUnack AcknowledgeAction = 16
+	// AcknowledgeSuppress suppresses the event, from Zabbix 6.4
+	AcknowledgeSuppress AcknowledgeAction = 32
+	// AcknowledgeUnsuppress unsuppresses the event, from Zabbix 6.4
+	AcknowledgeUnsuppress AcknowledgeAction = 64
+	// AcknowledgeRankCause changes the event to a cause, from Zabbix 7.0
+	AcknowledgeRankCause AcknowledgeAction = 128
+	// AcknowledgeRankSymptom changes the event to a symptom of CauseEventID, from Zabbix 7.0
+	AcknowledgeRankSymptom AcknowledgeAction = 256
+)
+
+// Tag is a tag of an event, a problem, or a host.
+type Tag struct {
+	Tag   string `json:"tag"`
+	Value string `json:"value"`
+}
+
+// Tags is an array of Tag
+type Tags []Tag
+
+// Event represent Zabbix event object
+// https://www.zabbix.com/documentation/current/manual/api/reference/event/object
+type Event struct {
+	EventID       string          `json:"eventid"`
+	Source        EventType       `json:"source"`
+	Object        EventObjectType `json:"object,string"`
+	ObjectID      string          `json:"objectid"`
+	Clock         time.Time       `json:"clock"`        // with nanoseconds
+	Value         ValueType       `json:"value,string"` // OK or Problem for trigger events
+	Acknowledged  int             `json:"acknowledged,string"`
+	Name          string          `json:"name"`
+	Severity      SeverityType    `json:"severity,string"`
+	REventID      string          `json:"r_eventid"`               // recovery event
+	CEventID      string          `json:"c_eventid"`               // event closing the problem
+	CauseEventID  string          `json:"cause_eventid,omitempty"` // cause of symptom events, from Zabbix 7.0
+	CorrelationID string          `json:"correlationid"`
+	UserID        string          `json:"userid"` // user who closed the problem
+	Suppressed    int             `json:"suppressed,string"`
+	OpData        string          `json:"opdata"`
+
+	// Fields below used if specified selectHosts, selectRelatedObject, select_acknowledges, selectTags
+	// or selectSuppressionData parameters
+	Hosts           Hosts               `json:"hosts,omitempty"`
+	RelatedObject   json.RawMessage     `json:"relatedObject,omitempty"` // see DecodeRelatedObject
+	Acknowledges    Acknowledges        `json:"acknowledges,omitempty"`
+	Tags            Tags                `json:"tags,omitempty"`
+	SuppressionData SuppressionDataList `json:"suppression_data,omitempty"`
+}
+
+// Events is an array of Event
+type Events []Event
+
+// UnmarshalJSON decodes the clock and ns fields of the event into Clock.
+func (e *Event) UnmarshalJSON(b []byte) error {
+	type event Event
+	raw := struct {
+		*event
+		Clock string `json:"clock"`
+		NS    string `json:"ns"`
+	}{event: (*event)(e)}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	e.Clock = unixTime(raw.Clock, raw.NS)
+	return nil
+}
+
+// DecodeRelatedObject Decodes the object selected by selectRelatedObject into v,
+// like a *Trigger for TriggerObject events or an *Item for ItemObject events.
+func (e *Event) DecodeRelatedObject(v interface{}) error {
+	if len(e.RelatedObject) == 0 {
+		return fmt.Errorf("zabbix: no related object of event %s, see selectRelatedObject", e.EventID)
+	}
+	return json.Unmarshal(e.RelatedObject, v)
+}
+
+// Acknowledge is an update of an event, by a user or an action.
+// https://www.zabbix.com/documentation/current/manual/api/reference/event/object#acknowledges
+type Acknowledge struct {
+	AcknowledgeID string            `json:"acknowledgeid"`
+	UserID        string            `json:"userid"`
+	EventID       string            `json:"eventid"`
+	Clock         time.Time         `json:"clock"`
+	Message       string            `json:"message"`
+	Action        AcknowledgeAction `json:"action,string"`
+	OldSeverity   SeverityType      `json:"old_severity,string"`
+	NewSeverity   SeverityType      `json:"new_severity,string"`
+	SuppressUntil time.Time         `json:"suppress_until"` // zero for indefinite suppression
+	TaskID        string            `json:"taskid"`
+	Username      string            `json:"username"`
+	Name          string            `json:"name"`
+	Surname       string            `json:"surname"`
+}
+
+// Acknowledges is an array of Acknowledge
+type Acknowledges []Acknowledge
+
+// UnmarshalJSON decodes the Unix times of the update.
+func (a *Acknowledge) UnmarshalJSON(b []byte) error {
+	type acknowledge Acknowledge
+	raw := struct {
+		*acknowledge
+		Clock         string `json:"clock"`
+		SuppressUntil string `json:"suppress_until"`
+	}{acknowledge: (*acknowledge)(a)}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	a.Clock = unixTime(raw.Clock, "")
+	a.SuppressUntil = unixTime(raw.SuppressUntil, "")
+	return nil
+}
+
+// SuppressionData tells why an event is suppressed, by a maintenance or a user.
+type SuppressionData struct {
+	MaintenanceID string    `json:"maintenanceid"`
+	UserID        string    `json:"userid"`
+	SuppressUntil time.Time `json:"suppress_until"` // zero for indefinite suppression
+}
+
+// SuppressionDataList is an array of SuppressionData
+type SuppressionDataList []SuppressionData
+
+// UnmarshalJSON decodes the Unix time of the suppression end.
+func (s *SuppressionData) UnmarshalJSON(b []byte) error {
+	type suppressionData SuppressionData
+	raw := struct {
+		*suppressionData
+		SuppressUntil string `json:"suppress_until"`
+	}{suppressionData: (*suppressionData)(s)}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	s.SuppressUntil = unixTime(raw.SuppressUntil, "")
+	return nil
+}
+
+// unixTime returns the time of Zabbix Unix seconds and nanoseconds, zero for "0" or "".
+func unixTime(sec, nsec string) time.Time {
+	s, _ := strconv.ParseInt(sec, 10, 64)
+	if s == 0 {
+		return time.Time{}
+	}
+	ns, _ := strconv.ParseInt(nsec, 10, 64)
+	return time.Unix(s, ns)
+}
+
+// EventsGet Wrapper for event.get
+// https://www.zabbix.com/documentation/current/manual/api/reference/event/get
+func (api *API) EventsGet(params Params) (res Events, err error) {
+	return api.EventsGetContext(context.Background(), params)
+}
+
+// EventsGetContext is like EventsGet but uses ctx for the underlying requests.
+func (api *API) EventsGetContext(ctx context.Context, params Params) (res Events, err error) {
+	return NewRepository[Event](api).Get(ctx, params)
+}
+
+// EventGetByID Gets event by Id only if there is exactly 1 matching event.
+func (api *API) EventGetByID(id string) (res *Event, err error) {
+	return api.EventGetByIDContext(context.Background(), id)
+}
+
+// EventGetByIDContext is like EventGetByID but uses ctx for the underlying requests.
+func (api *API) EventGetByIDContext(ctx context.Context, id string) (res *Event, err error) {
+	return NewRepository[Event](api).GetByID(ctx, id)
+}
+
+// AcknowledgeRequest is an update of events by event.acknowledge.
+// Action tells which of the other fields are used.
+type AcknowledgeRequest struct {
+	EventIDs      []string
+	Action        AcknowledgeAction
+	Message       string       // with AcknowledgeMessage
+	Severity      SeverityType // with AcknowledgeSeverity
+	SuppressUntil time.Time    // with AcknowledgeSuppress, zero to suppress indefinitely
+	CauseEventID  string       // with AcknowledgeRankSymptom
+}
+
+// Validate Returns an error matching ErrInvalidParams if the actions of req are incompatible
+// or do not match its other fields.
+func (req *AcknowledgeRequest) Validate() error {
+	a := req.Action
+	switch {
+	case len(req.EventIDs) == 0:
+		return fmt.Errorf("%w: no event to acknowledge", ErrInvalidParams)
+	case a <= 0 || a >= 2*AcknowledgeRankSymptom:
+		return fmt.Errorf("%w: invalid acknowledge action %d", ErrInvalidParams, a)
+	case a&AcknowledgeAck != 0 && a&AcknowledgeUnack != 0:
+		return fmt.Errorf("%w: cannot both acknowledge and unacknowledge events", ErrInvalidParams)
+	case a&AcknowledgeSuppress != 0 && a&AcknowledgeUnsuppress != 0:
+		return fmt.Errorf("%w: cannot both suppress and unsuppress events", ErrInvalidParams)
+	case a&AcknowledgeRankCause != 0 && a&AcknowledgeRankSymptom != 0:
+		return fmt.Errorf("%w: cannot rank events both as cause and as symptom", ErrInvalidParams)
+	case (a&AcknowledgeMessage != 0) != (req.Message != ""):
+		return fmt.Errorf("%w: a message needs AcknowledgeMessage, and AcknowledgeMessage a message", ErrInvalidParams)
+	case a&AcknowledgeSeverity != 0 && (req.Severity < NotClassified || req.Severity > Critical):
+		return fmt.Errorf("%w: invalid severity %d", ErrInvalidParams, req.Severity)
+	case !req.SuppressUntil.IsZero() && a&AcknowledgeSuppress == 0:
+		return fmt.Errorf("%w: SuppressUntil needs AcknowledgeSuppress", ErrInvalidParams)
+	case (a&AcknowledgeRankSymptom != 0) != (req.CauseEventID != ""):
+		return fmt.Errorf("%w: a cause event needs AcknowledgeRankSymptom, and AcknowledgeRankSymptom a cause event", ErrInvalidParams)
+	}
+	return nil
+}
+
+// params returns the event.acknowledge params of req.
+func (req *AcknowledgeRequest) params() Params {
+	p := Params{"eventids": req.EventIDs, "action": req.Action}
+	if req.Action&AcknowledgeMessage != 0 {
+		p["message"] = req.Message
+	}
+	if req.Action&AcknowledgeSeverity != 0 {
+		p["severity"] = req.Severity
+	}
+	if req.Action&AcknowledgeSuppress != 0 {
+		p["suppress_until"] = 0
+		if !req.SuppressUntil.IsZero() {
+			p["suppress_until"] = req.SuppressUntil.Unix()
+		}
+	}
+	if req.Action&AcknowledgeRankSymptom != 0 {
+		p["cause_eventid"] = req.CauseEventID
+	}
+	return p
+}
+
+// EventsAcknowledge Wrapper for event.acknowledge, returns the IDs of the updated events.
+// req is validated before it is sent, and actions the server version lacks return an *UnsupportedError.
+// https://www.zabbix.com/documentation/current/manual/api/reference/event/acknowledge
+func (api *API) EventsAcknowledge(req AcknowledgeRequest) (eventIDs []string, err error) {
+	return api.EventsAcknowledgeContext(context.Background(), req)
+}
+
+// EventsAcknowledgeContext is like EventsAcknowledge but uses ctx for the HTTP request.
+func (api *API) EventsAcknowledgeContext(ctx context.Context, req AcknowledgeRequest) (eventIDs []string, err error) {
+	if err = req.Validate(); err != nil {
+		return
+	}
+	if req.Action&(AcknowledgeSuppress|AcknowledgeUnsuppress) != 0 {
+		if err = api.require(ctx, FeatureEventSuppression); err != nil {
+			return
+		}
+	}
+	if req.Action&(AcknowledgeRankCause|AcknowledgeRankSymptom) != 0 {
+		if err = api.require(ctx, FeatureEventRank); err != nil {
+			return
+		}
+	}
+
+	response, err := api.CallWithErrorContext(ctx, "event.acknowledge", req.params())
+	if err != nil {
+		return
+	}
+	return NewRepository[Event](api).resultIDs(response.Result)
+}
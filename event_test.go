@@ -0,0 +1,129 @@
+package zabbix_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	zapi "github.com/claranet/go-zabbix-api"
+)
+
+func TestEventsGet(t *testing.T) {
+	api := testFakeAPI(t, "7.0.0", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jsonrpc":"2.0","result":[{
+			"eventid":"42","source":"0","object":"0","objectid":"13","clock":"1700000000","ns":"500",
+			"value":"1","acknowledged":"1","name":"CPU is busy","severity":"4","r_eventid":"0",
+			"cause_eventid":"0","suppressed":"1","opdata":"95 %",
+			"hosts":[{"hostid":"10084","host":"server"}],
+			"relatedObject":{"triggerid":"13","description":"CPU is busy"},
+			"acknowledges":[{"acknowledgeid":"1","userid":"1","clock":"1700000060","message":"on it",
+				"action":"6","old_severity":"0","new_severity":"0","suppress_until":"0","username":"Admin"}],
+			"tags":[{"tag":"service","value":"web"}],
+			"suppression_data":[{"maintenanceid":"0","userid":"1","suppress_until":"1700003600"}]
+		}],"id":1}`))
+	})
+
+	events, err := api.EventsGet(zapi.Params{"select_acknowledges": "extend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events))
+	}
+	e := events[0]
+	if e.EventID != "42" || e.Source != zapi.TriggerEvent || e.Object != zapi.TriggerObject || e.Value != zapi.Problem ||
+		e.Severity != zapi.High || e.Suppressed != 1 || e.OpData != "95 %" {
+		t.Errorf("Unexpected event %#v", e)
+	}
+	if !e.Clock.Equal(time.Unix(1700000000, 500)) {
+		t.Errorf("Unexpected clock %v", e.Clock)
+	}
+	if len(e.Hosts) != 1 || e.Hosts[0].HostID != "10084" || len(e.Tags) != 1 || e.Tags[0] != (zapi.Tag{Tag: "service", Value: "web"}) {
+		t.Errorf("Unexpected hosts or tags %#v %#v", e.Hosts, e.Tags)
+	}
+	if len(e.Acknowledges) != 1 || e.Acknowledges[0].Action != zapi.AcknowledgeAck|zapi.AcknowledgeMessage ||
+		!e.Acknowledges[0].Clock.Equal(time.Unix(1700000060, 0)) || !e.Acknowledges[0].SuppressUntil.IsZero() {
+		t.Errorf("Unexpected acknowledges %#v", e.Acknowledges)
+	}
+	if len(e.SuppressionData) != 1 || !e.SuppressionData[0].SuppressUntil.Equal(time.Unix(1700003600, 0)) {
+		t.Errorf("Unexpected suppression data %#v", e.SuppressionData)
+	}
+
+	var trigger zapi.Trigger
+	if err = e.DecodeRelatedObject(&trigger); err != nil {
+		t.Fatal(err)
+	}
+	if trigger.TriggerID != "13" {
+		t.Errorf("Unexpected related trigger %#v", trigger)
+	}
+}
+
+func TestEventsAcknowledge(t *testing.T) {
+	var params map[string]interface{}
+	api := testFakeAPI(t, "7.0.0", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Params map[string]interface{} `json:"params"`
+		}
+		testDecodeRequest(r, &req)
+		params = req.Params
+		w.Write([]byte(`{"jsonrpc":"2.0","result":{"eventids":[42]},"id":1}`))
+	})
+
+	until := time.Unix(1700003600, 0)
+	ids, err := api.EventsAcknowledge(zapi.AcknowledgeRequest{
+		EventIDs:      []string{"42"},
+		Action:        zapi.AcknowledgeAck | zapi.AcknowledgeMessage | zapi.AcknowledgeSuppress,
+		Message:       "on it",
+		SuppressUntil: until,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != "42" {
+		t.Errorf("Unexpected event IDs %v", ids)
+	}
+	if params["action"] != float64(38) || params["message"] != "on it" || params["suppress_until"] != float64(until.Unix()) {
+		t.Errorf("Unexpected params %v", params)
+	}
+	if _, ok := params["severity"]; ok {
+		t.Errorf("Unexpected severity in %v", params)
+	}
+}
+
+func TestAcknowledgeRequestValidate(t *testing.T) {
+	ids := []string{"42"}
+	for name, req := range map[string]zapi.AcknowledgeRequest{
+		"no events":             {Action: zapi.AcknowledgeAck},
+		"no action":             {EventIDs: ids},
+		"unknown action":        {EventIDs: ids, Action: 512},
+		"ack and unack":         {EventIDs: ids, Action: zapi.AcknowledgeAck | zapi.AcknowledgeUnack},
+		"suppress and unsupp":   {EventIDs: ids, Action: zapi.AcknowledgeSuppress | zapi.AcknowledgeUnsuppress},
+		"cause and symptom":     {EventIDs: ids, Action: zapi.AcknowledgeRankCause | zapi.AcknowledgeRankSymptom, CauseEventID: "1"},
+		"message without flag":  {EventIDs: ids, Action: zapi.AcknowledgeAck, Message: "on it"},
+		"flag without message":  {EventIDs: ids, Action: zapi.AcknowledgeMessage},
+		"invalid severity":      {EventIDs: ids, Action: zapi.AcknowledgeSeverity, Severity: 6},
+		"suppress until alone":  {EventIDs: ids, Action: zapi.AcknowledgeAck, SuppressUntil: time.Now()},
+		"symptom without cause": {EventIDs: ids, Action: zapi.AcknowledgeRankSymptom},
+	} {
+		if err := req.Validate(); !errors.Is(err, zapi.ErrInvalidParams) {
+			t.Errorf("%s: expected ErrInvalidParams, got %v", name, err)
+		}
+	}
+
+	req := zapi.AcknowledgeRequest{EventIDs: ids, Action: zapi.AcknowledgeClose | zapi.AcknowledgeSeverity, Severity: zapi.Critical}
+	if err := req.Validate(); err != nil {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestEventsAcknowledgeUnsupported(t *testing.T) {
+	api := testFakeAPI(t, "6.4.0", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Unexpected request")
+	})
+
+	_, err := api.EventsAcknowledge(zapi.AcknowledgeRequest{EventIDs: []string{"42"}, Action: zapi.AcknowledgeRankCause})
+	if !errors.Is(err, zapi.ErrVersionUnsupported) {
+		t.Errorf("Expected ErrVersionUnsupported, got %v", err)
+	}
+}
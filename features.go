@@ -0,0 +1,298 @@
+package zabbix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/go-version"
+)
+
+// Feature is a part of the Zabbix API which only some server versions have.
+//
+//	if api.Supports(zabbix.FeatureTemplateGroups) {
+//		groups, err = api.TemplateGroupsGet(params)
+//	}
+type Feature string
+
+const (
+	// FeatureActionDefaultMessages is the def_*, r_* and ack_* message fields of actions, removed in 5.0.
+	FeatureActionDefaultMessages Feature = "action default messages"
+	// FeatureApplications is the application API, removed in 5.4.
+	FeatureApplications Feature = "application API"
+	// FeatureRoles is the role API and the roleid of users, replacing their type in 5.2.
+	FeatureRoles Feature = "role API"
+	// FeatureUsername is the username of users, replacing their alias in 5.4.
+	FeatureUsername Feature = "user username"
+	// FeatureAPITokens is authentication with API tokens, new in 5.4.
+	FeatureAPITokens Feature = "API tokens"
+	// FeatureTemplateGroups is the templategroup API, new in 6.2.
+	FeatureTemplateGroups Feature = "templategroup API"
+	// FeatureBearerAuth is the "Authorization: Bearer" header, replacing the "auth" request field in 6.4.
+	FeatureBearerAuth Feature = "bearer authentication"
+	// FeatureMessageFormat is the message_format of media types, replacing their content_type in 7.0.
+	FeatureMessageFormat Feature = "media type message_format"
+	// FeatureEventSuppression is the manual suppression of events by event.acknowledge, new in 6.4.
+	FeatureEventSuppression Feature = "event suppression"
+	// FeatureEventRank is the ranking of events as cause or symptom by event.acknowledge, new in 7.0.
+	FeatureEventRank Feature = "event rank"
+	// FeatureHistoryPush is the history.push method, new in 7.0.
+	FeatureHistoryPush Feature = "history.push"
+)
+
+// versionRange is the server versions having a feature.
+type versionRange struct {
+	since string // first version with the feature, empty for all
+	until string // first version without the feature, empty for all
+}
+
+// features registers the versions of each Feature.
+var features = map[Feature]versionRange{
+	FeatureActionDefaultMessages: {until: "5.0"},
+	FeatureApplications:          {until: "5.4"},
+	FeatureRoles:                 {since: "5.2"},
+	FeatureUsername:              {since: "5.4"},
+	FeatureAPITokens:             {since: "5.4"},
+	FeatureTemplateGroups:        {since: "6.2"},
+	FeatureBearerAuth:            {since: "6.4"},
+	FeatureMessageFormat:         {since: "7.0"},
+	FeatureEventSuppression:      {since: "6.4"},
+	FeatureEventRank:             {since: "7.0"},
+	FeatureHistoryPush:           {since: "7.0"},
+}
+
+// objectFeatures are the features needed by the APIs of object types, by API prefix.
+var objectFeatures = map[string]Feature{
+	"application":   FeatureApplications,
+	"role":          FeatureRoles,
+	"templategroup": FeatureTemplateGroups,
+}
+
+// fieldChange translates a field of objects for servers with or without a feature.
+type fieldChange struct {
+	feature Feature
+	field   string // field of servers with the feature
+	legacy  string // field of servers without the feature, empty if they have none
+}
+
+// fieldChanges are the translated fields of object types, by API prefix.
+// The roleid of users having the default roles 1, 2 and 3 is the number of their legacy type.
+var fieldChanges = map[string][]fieldChange{
+	"action": {
+		{FeatureActionDefaultMessages, "def_shortdata", ""},
+		{FeatureActionDefaultMessages, "def_longdata", ""},
+		{FeatureActionDefaultMessages, "r_shortdata", ""},
+		{FeatureActionDefaultMessages, "r_longdata", ""},
+		{FeatureActionDefaultMessages, "ack_shortdata", ""},
+		{FeatureActionDefaultMessages, "ack_longdata", ""},
+	},
+	"mediatype": {
+		{FeatureMessageFormat, "message_format", "content_type"},
+	},
+	"user": {
+		{FeatureUsername, "username", "alias"},
+		{FeatureRoles, "roleid", "type"},
+	},
+}
+
+// Supports reports whether the server version has feature f.
+// It is false for unknown features, and while the server version is unknown.
+func (api *API) Supports(f Feature) bool {
+	r, ok := features[f]
+	serverVersion := api.serverVersion()
+	if !ok || serverVersion == nil {
+		return false
+	}
+	if r.since != "" && serverVersion.LessThan(version.Must(version.NewVersion(r.since))) {
+		return false
+	}
+	return r.until == "" || serverVersion.LessThan(version.Must(version.NewVersion(r.until)))
+}
+
+// UnsupportedError is returned without calling the server for features its version lacks.
+// It matches ErrVersionUnsupported.
+type UnsupportedError struct {
+	Feature Feature
+	Version string // server version
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("zabbix: %s not supported by Zabbix %s", e.Feature, e.Version)
+}
+
+// Is makes errors.Is match e with ErrVersionUnsupported.
+func (e *UnsupportedError) Is(target error) bool {
+	return target == ErrVersionUnsupported
+}
+
+// require returns an *UnsupportedError if the server version is known and lacks f.
+func (api *API) require(ctx context.Context, f Feature) error {
+	if err := api.ensureServerVersion(ctx); err != nil {
+		return err
+	}
+	serverVersion := api.serverVersion()
+	if serverVersion == nil || api.Supports(f) {
+		return nil
+	}
+	return &UnsupportedError{f, serverVersion.String()}
+}
+
+// requireObject returns an *UnsupportedError if the server version lacks the API prefix.
+func (api *API) requireObject(ctx context.Context, prefix string) error {
+	if f, ok := objectFeatures[prefix]; ok {
+		return api.require(ctx, f)
+	}
+	return api.ensureServerVersion(ctx)
+}
+
+// encodeObjects returns objects, of the API prefix, as params for the server version:
+// fields unknown to the server are translated to the fields it knows, or dropped.
+func (api *API) encodeObjects(prefix string, objects interface{}) (params interface{}, err error) {
+	changes := fieldChanges[prefix]
+	if len(changes) == 0 || api.serverVersion() == nil {
+		return objects, nil
+	}
+	b, err := json.Marshal(objects)
+	if err != nil {
+		return
+	}
+	var values []map[string]json.RawMessage
+	if err = json.Unmarshal(b, &values); err != nil {
+		return
+	}
+	for _, v := range values {
+		for _, c := range changes {
+			from, to := c.legacy, c.field
+			if !api.Supports(c.feature) {
+				from, to = c.field, c.legacy
+			}
+			if raw, ok := v[from]; ok && from != "" {
+				if _, set := v[to]; !set && to != "" {
+					v[to] = raw
+				}
+				delete(v, from)
+			}
+		}
+	}
+	return values, nil
+}
+
+// encodeGetParams returns the params of <prefix>.get with the fields of the output, filter,
+// search and sortfield params translated like encodeObjects does.
+func (api *API) encodeGetParams(prefix string, params Params) Params {
+	changes := fieldChanges[prefix]
+	if len(changes) == 0 || api.serverVersion() == nil {
+		return params
+	}
+	renames := map[string]string{}
+	for _, c := range changes {
+		if c.legacy == "" {
+			continue
+		}
+		if api.Supports(c.feature) {
+			renames[c.legacy] = c.field
+		} else {
+			renames[c.field] = c.legacy
+		}
+	}
+
+	p := make(Params, len(params))
+	for k, v := range params {
+		p[k] = v
+	}
+	for _, key := range []string{"output", "sortfield"} {
+		if fields, ok := stringList(p[key]); ok {
+			for i, field := range fields {
+				if to, ok := renames[field]; ok {
+					fields[i] = to
+				}
+			}
+			p[key] = fields
+		}
+	}
+	for _, key := range []string{"filter", "search"} {
+		m := reflect.ValueOf(p[key])
+		if m.Kind() != reflect.Map || m.Type().Key().Kind() != reflect.String {
+			continue
+		}
+		renamed := make(map[string]interface{}, m.Len())
+		for iter := m.MapRange(); iter.Next(); {
+			field := iter.Key().String()
+			if to, ok := renames[field]; ok {
+				// the field known to the server wins over its translation
+				if m.MapIndex(reflect.ValueOf(to).Convert(m.Type().Key())).IsValid() {
+					continue
+				}
+				field = to
+			}
+			renamed[field] = iter.Value().Interface()
+		}
+		p[key] = renamed
+	}
+	return p
+}
+
+// stringList returns a copy of v if it is a string or a slice of strings.
+func stringList(v interface{}) ([]string, bool) {
+	switch v := v.(type) {
+	case string:
+		return []string{v}, v != "extend" && v != "count" && v != "shorten" && v != "refer"
+	case []string:
+		return append([]string(nil), v...), true
+	case []interface{}:
+		res := make([]string, len(v))
+		for i, field := range v {
+			s, ok := field.(string)
+			if !ok {
+				return nil, false
+			}
+			res[i] = s
+		}
+		return res, true
+	}
+	return nil, false
+}
+
+// decodeObjects decodes raw objects of the API prefix, an array or a single object, into res.
+// Fields returned by the server version instead of the current ones fill them too.
+func (api *API) decodeObjects(prefix string, raw json.RawMessage, res interface{}) error {
+	var changes []fieldChange
+	for _, c := range fieldChanges[prefix] {
+		if c.legacy != "" && !api.Supports(c.feature) && api.serverVersion() != nil {
+			changes = append(changes, c)
+		}
+	}
+	raw = bytes.TrimSpace(raw)
+	single := bytes.HasPrefix(raw, []byte("{"))
+	if len(changes) == 0 || !single && !bytes.HasPrefix(raw, []byte("[")) {
+		return json.Unmarshal(raw, res)
+	}
+
+	var values []map[string]json.RawMessage
+	if single {
+		raw = append(append([]byte("["), raw...), ']')
+	}
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return err
+	}
+	for _, v := range values {
+		for _, c := range changes {
+			if _, set := v[c.field]; !set && v[c.legacy] != nil {
+				v[c.field] = v[c.legacy]
+			}
+		}
+	}
+	var b []byte
+	var err error
+	if single {
+		b, err = json.Marshal(values[0])
+	} else {
+		b, err = json.Marshal(values)
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, res)
+}
@@ -0,0 +1,206 @@
+package zabbix_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	zapi "github.com/claranet/go-zabbix-api"
+	"github.com/claranet/go-zabbix-api/zabbixtest"
+)
+
+// testServerAPI returns an API logged in a zabbixtest server reporting version.
+func testServerAPI(t *testing.T, version string) (*zabbixtest.Server, *zapi.API) {
+	srv := zabbixtest.NewServer(zabbixtest.WithVersion(version))
+	t.Cleanup(srv.Close)
+	api, err := zapi.NewAPI(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = api.Login(zabbixtest.AdminUser, zabbixtest.AdminPassword); err != nil {
+		t.Fatal(err)
+	}
+	return srv, api
+}
+
+func TestSupports(t *testing.T) {
+	for _, test := range []struct {
+		version string
+		feature zapi.Feature
+		want    bool
+	}{
+		{"4.0.0", zapi.FeatureActionDefaultMessages, true},
+		{"5.0.30", zapi.FeatureActionDefaultMessages, false},
+		{"5.0.30", zapi.FeatureApplications, true},
+		{"5.4.0", zapi.FeatureApplications, false},
+		{"5.0.30", zapi.FeatureRoles, false},
+		{"5.2.0", zapi.FeatureRoles, true},
+		{"6.0.25", zapi.FeatureTemplateGroups, false},
+		{"6.2.0", zapi.FeatureTemplateGroups, true},
+		{"6.4.0", zapi.FeatureMessageFormat, false},
+		{"7.0.0", zapi.FeatureMessageFormat, true},
+		{"7.0.0", zapi.Feature("unknown"), false},
+	} {
+		api, err := zapi.NewAPIWithOptions("http://127.0.0.1:1/", zapi.WithServerVersion(test.version))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := api.Supports(test.feature); got != test.want {
+			t.Errorf("%s on %s: expected %v, got %v", test.feature, test.version, test.want, got)
+		}
+	}
+
+	api, err := zapi.NewAPIWithOptions("http://127.0.0.1:1/", zapi.WithLazyVersionDetection())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if api.Supports(zapi.FeatureRoles) {
+		t.Error("Expected no feature while the version is unknown")
+	}
+}
+
+func TestUnsupportedObject(t *testing.T) {
+	srv, api := testServerAPI(t, "6.0.25")
+
+	_, err := api.ApplicationsGet(zapi.Params{})
+	var e *zapi.UnsupportedError
+	if !errors.As(err, &e) || e.Feature != zapi.FeatureApplications || e.Version != "6.0.25" {
+		t.Fatalf("Expected UnsupportedError, got %v", err)
+	}
+	if !errors.Is(err, zapi.ErrVersionUnsupported) {
+		t.Errorf("Expected ErrVersionUnsupported, got %v", err)
+	}
+	if err = api.TemplateGroupsCreate(zapi.TemplateGroups{{Name: "group"}}); !errors.As(err, &e) {
+		t.Errorf("Expected UnsupportedError, got %v", err)
+	}
+	if len(srv.Objects("templategroup")) != 0 {
+		t.Error("Unexpected template group")
+	}
+}
+
+func TestLegacyUserFields(t *testing.T) {
+	srv, api := testServerAPI(t, "5.0.30")
+
+	users := zapi.Users{{Username: "legacy", Password: "secret-password", RoleID: "1", UsrGrps: zapi.UserGroups{{GroupID: "7"}}}}
+	if err := api.UsersCreate(users); err != nil {
+		t.Fatal(err)
+	}
+	defer api.UsersDeleteByIds([]string{users[0].UserID})
+
+	for _, u := range srv.Objects("user") {
+		if u["userid"] != users[0].UserID {
+			continue
+		}
+		if u["alias"] != "legacy" || u["type"] != "1" || u["username"] != nil || u["roleid"] != nil {
+			t.Errorf("Expected the legacy fields, got %v", u)
+		}
+	}
+
+	got, err := api.UsersGet(zapi.Params{"filter": zapi.Params{"username": "legacy"}, "output": []string{"userid", "username", "roleid"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Username != "legacy" || got[0].RoleID != "1" {
+		t.Errorf("Expected the user with its current fields, got %#v", got)
+	}
+}
+
+func TestMessageFormat(t *testing.T) {
+	for version, fields := range map[string][2]string{
+		"6.0.25": {"content_type", "message_format"},
+		"7.0.0":  {"message_format", "content_type"},
+	} {
+		srv, api := testServerAPI(t, version)
+
+		mediaTypes := []zapi.MediaType{
+			{Name: "legacy", Type: zapi.MediaTypeSMS, ContentType: 1},
+			{Name: "current", Type: zapi.MediaTypeSMS, MessageFormat: 1},
+		}
+		if err := zapi.NewRepository[zapi.MediaType](api).Create(context.Background(), mediaTypes); err != nil {
+			t.Fatal(err)
+		}
+		for _, mt := range srv.Objects("mediatype") {
+			if mt["name"] != "legacy" && mt["name"] != "current" {
+				continue
+			}
+			if mt[fields[0]] != "1" || mt[fields[1]] != nil {
+				t.Errorf("%s: expected %s, got %v", version, fields[0], mt)
+			}
+		}
+	}
+}
+
+func TestActionDefaultMessages(t *testing.T) {
+	srv, api := testServerAPI(t, "5.0.30")
+
+	actions := zapi.Actions{{
+		Name:           "default messages",
+		EventSource:    zapi.TriggerEvent,
+		Period:         "1h",
+		DefaultSubject: "Problem: {EVENT.NAME}",
+		DefaultMessage: "Problem started at {EVENT.TIME}",
+		Operations: zapi.ActionOperations{{
+			OperationType:     zapi.SendMessage,
+			Message:           &zapi.ActionOperationMessage{DefaultMessage: "1"},
+			MessageUserGroups: zapi.ActionOperationMessageUserGroups{{UserGroupID: "7"}},
+		}},
+	}}
+	if err := api.ActionsCreate(actions); err != nil {
+		t.Fatal(err)
+	}
+	for _, action := range srv.Objects("action") {
+		if action["def_shortdata"] != nil || action["def_longdata"] != nil {
+			t.Errorf("Expected no default messages, got %v", action)
+		}
+	}
+}
+
+func TestLegacyUserFieldsStream(t *testing.T) {
+	_, api := testServerAPI(t, "5.0.30")
+	ctx := context.Background()
+
+	users := zapi.Users{
+		{Username: "legacy-1", Password: "secret-password", RoleID: "1", UsrGrps: zapi.UserGroups{{GroupID: "7"}}},
+		{Username: "legacy-2", Password: "secret-password", RoleID: "2", UsrGrps: zapi.UserGroups{{GroupID: "7"}}},
+	}
+	if err := api.UsersCreate(users); err != nil {
+		t.Fatal(err)
+	}
+	repo := zapi.NewRepository[zapi.User](api)
+	params := zapi.Params{
+		"filter":    zapi.Params{"username": []string{"legacy-1", "legacy-2"}},
+		"output":    []string{"userid", "username", "roleid"},
+		"sortfield": "username",
+	}
+
+	var got []string
+	s := repo.Iter(ctx, params)
+	defer s.Close()
+	for s.Next() {
+		got = append(got, s.Value().Username+":"+s.Value().RoleID)
+	}
+	if err := s.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != "legacy-1:1" || got[1] != "legacy-2:2" {
+		t.Errorf("Expected the streamed users with their current fields, got %v", got)
+	}
+
+	got = nil
+	pager := repo.Pager(params, 1)
+	for pager.Next(ctx) {
+		for _, u := range pager.Page() {
+			got = append(got, u.Username+":"+u.RoleID)
+		}
+	}
+	if err := pager.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != "legacy-1:1" || got[1] != "legacy-2:2" {
+		t.Errorf("Expected the paged users with their current fields, got %v", got)
+	}
+
+	if n, err := repo.Count(ctx, zapi.Params{"filter": zapi.Params{"username": "legacy-1"}}); err != nil || n != 1 {
+		t.Errorf("Expected 1 user, got %d: %v", n, err)
+	}
+}
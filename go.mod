@@ -1,5 +1,5 @@
 module github.com/claranet/go-zabbix-api
 
-go 1.18
+go 1.21
 
-require github.com/hashicorp/go-version v1.6.0 // indirect
+require github.com/hashicorp/go-version v1.6.0
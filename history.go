@@ -0,0 +1,206 @@
+package zabbix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// History is a value collected for an item, from the history table of its value type.
+// The Float and Unsigned values are parsed, Text is the value as returned by the server.
+// https://www.zabbix.com/documentation/current/manual/api/reference/history/object
+type History struct {
+	ItemID    string
+	Clock     time.Time // with nanoseconds
+	ValueType ValueType
+	Float     float64 // Float items
+	Unsigned  uint64  // Unsigned items
+	Text      string  // all items
+
+	// Fields below used only for Log items
+	LogID      string
+	Timestamp  time.Time // time of the log entry
+	Source     string
+	Severity   int
+	LogEventID int
+}
+
+// Histories is an array of History
+type Histories []History
+
+// historyFields are the indexes of the sort fields of history.get in History.
+var historyFields = func() map[string][]int {
+	t := reflect.TypeOf(History{})
+	itemID, _ := t.FieldByName("ItemID")
+	clock, _ := t.FieldByName("Clock")
+	return map[string][]int{"itemid": itemID.Index, "clock": clock.Index}
+}()
+
+// historyRecord is a history object as returned by history.get.
+type historyRecord struct {
+	ID         string `json:"id"`
+	ItemID     string `json:"itemid"`
+	Clock      string `json:"clock"`
+	NS         string `json:"ns"`
+	Value      string `json:"value"`
+	Timestamp  string `json:"timestamp"`
+	Source     string `json:"source"`
+	Severity   string `json:"severity"`
+	LogEventID string `json:"logeventid"`
+}
+
+// history returns the History of r, an object of the history table of valueType.
+func (r *historyRecord) history(valueType ValueType) (h History, err error) {
+	h = History{ItemID: r.ItemID, Clock: unixTime(r.Clock, r.NS), ValueType: valueType, Text: r.Value}
+	switch valueType {
+	case Float:
+		h.Float, err = strconv.ParseFloat(r.Value, 64)
+	case Unsigned:
+		h.Unsigned, err = strconv.ParseUint(r.Value, 10, 64)
+	case Log:
+		h.LogID, h.Timestamp, h.Source = r.ID, unixTime(r.Timestamp, ""), r.Source
+		h.Severity, _ = strconv.Atoi(r.Severity)
+		h.LogEventID, _ = strconv.Atoi(r.LogEventID)
+	}
+	if err != nil {
+		err = fmt.Errorf("zabbix: invalid value %q of item %s: %w", r.Value, r.ItemID, err)
+	}
+	return
+}
+
+// HistoryGet Wrapper for history.get of the values of items, read from the history table of their value type.
+// The items are got with one request per ValueType and chunk of ChunkSize items. The server sorts
+// and limits each request, so the merged results are sorted again by sortfield, "itemid" or "clock",
+// and cut to limit. params may set other options, like time_from.
+// https://www.zabbix.com/documentation/current/manual/api/reference/history/get
+func (api *API) HistoryGet(items Items, params Params) (res Histories, err error) {
+	return api.HistoryGetContext(context.Background(), items, params)
+}
+
+// HistoryGetContext is like HistoryGet but uses ctx for the HTTP requests.
+func (api *API) HistoryGetContext(ctx context.Context, items Items, params Params) (res Histories, err error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: no item to get the history of", ErrInvalidParams)
+	}
+	var valueTypes []ValueType
+	ids := make(map[ValueType][]string)
+	for _, item := range items {
+		if _, present := ids[item.ValueType]; !present {
+			valueTypes = append(valueTypes, item.ValueType)
+		}
+		ids[item.ValueType] = append(ids[item.ValueType], item.ItemID)
+	}
+	split := len(valueTypes) > 1
+	for _, ids := range ids {
+		split = split || api.chunked(len(ids))
+	}
+	var order chunkOrder
+	if split {
+		if order, err = newChunkOrder("history", params, historyFields); err != nil {
+			return
+		}
+	}
+
+	for _, valueType := range valueTypes {
+		ids := ids[valueType]
+		err = api.eachChunk(len(ids), func(lo, hi int) error {
+			p := make(Params, len(params)+3)
+			for k, v := range params {
+				p[k] = v
+			}
+			p["history"] = valueType
+			p["itemids"] = ids[lo:hi]
+			if _, present := p["output"]; !present {
+				p["output"] = "extend"
+			}
+
+			var records []historyRecord
+			if err := api.CallWithErrorParseContext(ctx, "history.get", p, &records); err != nil {
+				return err
+			}
+			for i := range records {
+				h, err := records[i].history(valueType)
+				if err != nil {
+					return err
+				}
+				res = append(res, h)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if split {
+		res = mergeChunks(order, res)
+	}
+	return
+}
+
+// HistoryClear Wrapper for history.clear, deletes the history and trends of the items.
+// https://www.zabbix.com/documentation/current/manual/api/reference/history/clear
+func (api *API) HistoryClear(itemIDs []string) (err error) {
+	return api.HistoryClearContext(context.Background(), itemIDs)
+}
+
+// HistoryClearContext is like HistoryClear but uses ctx for the HTTP requests.
+func (api *API) HistoryClearContext(ctx context.Context, itemIDs []string) (err error) {
+	return api.eachChunk(len(itemIDs), func(lo, hi int) error {
+		_, err := api.CallWithErrorContext(ctx, "history.clear", itemIDs[lo:hi])
+		return err
+	})
+}
+
+// HistoryValue is a value sent by history.push to a trapper or HTTP agent item,
+// given by ItemID or by Host and Key.
+type HistoryValue struct {
+	ItemID string
+	Host   string
+	Key    string
+	Value  string
+	Clock  time.Time // zero for the time of the server
+}
+
+// MarshalJSON encodes v as a history.push value.
+func (v HistoryValue) MarshalJSON() ([]byte, error) {
+	m := map[string]interface{}{"value": v.Value}
+	if v.ItemID != "" {
+		m["itemid"] = v.ItemID
+	} else {
+		m["host"], m["key"] = v.Host, v.Key
+	}
+	if !v.Clock.IsZero() {
+		m["clock"], m["ns"] = v.Clock.Unix(), v.Clock.Nanosecond()
+	}
+	return json.Marshal(m)
+}
+
+// HistoryPushResult is the result of a value sent by history.push.
+type HistoryPushResult struct {
+	ItemID string `json:"itemid"`
+	Error  string `json:"error"` // empty if the value was accepted
+}
+
+// HistoryPush Wrapper for history.push, returns the result of each value.
+// It needs Zabbix 7.0 and returns an *UnsupportedError before.
+// https://www.zabbix.com/documentation/current/manual/api/reference/history/push
+func (api *API) HistoryPush(values []HistoryValue) (res []HistoryPushResult, err error) {
+	return api.HistoryPushContext(context.Background(), values)
+}
+
+// HistoryPushContext is like HistoryPush but uses ctx for the HTTP request.
+func (api *API) HistoryPushContext(ctx context.Context, values []HistoryValue) (res []HistoryPushResult, err error) {
+	if err = api.require(ctx, FeatureHistoryPush); err != nil {
+		return
+	}
+	var result struct {
+		Data []HistoryPushResult `json:"data"`
+	}
+	if err = api.CallWithErrorParseContext(ctx, "history.push", values, &result); err != nil {
+		return
+	}
+	return result.Data, nil
+}
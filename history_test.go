@@ -0,0 +1,181 @@
+package zabbix_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	zapi "github.com/claranet/go-zabbix-api"
+)
+
+func TestHistoryGet(t *testing.T) {
+	var params map[string]interface{}
+	results := map[float64]string{
+		0: `[{"itemid":"1","clock":"1700000000","ns":"250","value":"0.75"}]`,
+		3: `[{"itemid":"3","clock":"1700000000","ns":"0","value":"18446744073709551615"}]`,
+		2: `[{"id":"9","itemid":"2","clock":"1700000000","ns":"0","timestamp":"1699999990","source":"sshd","severity":"4","logeventid":"7","value":"login failed"}]`,
+	}
+	api := testFakeAPI(t, "7.0.0", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Params map[string]interface{} `json:"params"`
+		}
+		testDecodeRequest(r, &req)
+		params = req.Params
+		w.Write([]byte(`{"jsonrpc":"2.0","result":` + results[params["history"].(float64)] + `,"id":1}`))
+	})
+
+	res, err := api.HistoryGet(zapi.Items{{ItemID: "1", ValueType: zapi.Float}}, zapi.Params{"limit": 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].Float != 0.75 || res[0].Text != "0.75" || !res[0].Clock.Equal(time.Unix(1700000000, 250)) {
+		t.Errorf("Unexpected float history %#v", res)
+	}
+	if params["limit"] != float64(10) || params["output"] != "extend" || len(params["itemids"].([]interface{})) != 1 {
+		t.Errorf("Unexpected params %v", params)
+	}
+
+	if res, err = api.HistoryGet(zapi.Items{{ItemID: "3", ValueType: zapi.Unsigned}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].Unsigned != 18446744073709551615 {
+		t.Errorf("Unexpected unsigned history %#v", res)
+	}
+
+	if res, err = api.HistoryGet(zapi.Items{{ItemID: "2", ValueType: zapi.Log}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].Text != "login failed" || res[0].Source != "sshd" || res[0].Severity != 4 ||
+		res[0].LogEventID != 7 || !res[0].Timestamp.Equal(time.Unix(1699999990, 0)) {
+		t.Errorf("Unexpected log history %#v", res)
+	}
+
+	if _, err = api.HistoryGet(nil, nil); !errors.Is(err, zapi.ErrInvalidParams) {
+		t.Errorf("Expected ErrInvalidParams without items, got %v", err)
+	}
+}
+
+func TestHistoryGetMixed(t *testing.T) {
+	var requests []map[string]interface{}
+	api := testFakeAPI(t, "7.0.0", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Params map[string]interface{} `json:"params"`
+		}
+		testDecodeRequest(r, &req)
+		requests = append(requests, req.Params)
+		var records []string
+		for _, id := range req.Params["itemids"].([]interface{}) {
+			records = append(records, `{"itemid":"`+id.(string)+`","clock":"170000000`+id.(string)+`","ns":"0","value":"`+id.(string)+`"}`)
+		}
+		// the IDs are sent sorted, so records are sorted by clock
+		if req.Params["sortorder"] == "DESC" {
+			for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+				records[i], records[j] = records[j], records[i]
+			}
+		}
+		if limit, ok := req.Params["limit"].(float64); ok && len(records) > int(limit) {
+			records = records[:int(limit)]
+		}
+		w.Write([]byte(`{"jsonrpc":"2.0","result":[` + strings.Join(records, ",") + `],"id":1}`))
+	})
+	api.ChunkSize = 2
+
+	items := zapi.Items{
+		{ItemID: "1", ValueType: zapi.Float},
+		{ItemID: "2", ValueType: zapi.Unsigned},
+		{ItemID: "3", ValueType: zapi.Float},
+		{ItemID: "4", ValueType: zapi.Float},
+	}
+	res, err := api.HistoryGet(items, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, h := range res {
+		got = append(got, fmt.Sprintf("%s:%v:%g:%d", h.ItemID, h.ValueType, h.Float, h.Unsigned))
+	}
+	if expected := []string{"1:0:1:0", "3:0:3:0", "4:0:4:0", "2:3:0:2"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("History = %v, expected %v", got, expected)
+	}
+	var sent []string
+	for _, p := range requests {
+		sent = append(sent, fmt.Sprint(p["history"], p["itemids"]))
+	}
+	if expected := []string{"0 [1 3]", "0 [4]", "3 [2]"}; !reflect.DeepEqual(sent, expected) {
+		t.Errorf("Requests = %v, expected %v", sent, expected)
+	}
+
+	res, err = api.HistoryGet(items, zapi.Params{"sortfield": "clock", "sortorder": zapi.SortDesc, "limit": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = nil
+	for _, h := range res {
+		got = append(got, h.ItemID)
+	}
+	if expected := []string{"4", "3", "2"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected the last 3 values of all requests, got %v", got)
+	}
+
+	if _, err = api.HistoryGet(items, zapi.Params{"sortfield": "value"}); !errors.Is(err, zapi.ErrInvalidParams) {
+		t.Errorf("Expected ErrInvalidParams for a sort field which cannot be merged, got %v", err)
+	}
+}
+
+func TestHistoryClearChunked(t *testing.T) {
+	var requests [][]string
+	api := testFakeAPI(t, "7.0.0", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Params []string `json:"params"`
+		}
+		testDecodeRequest(r, &req)
+		requests = append(requests, req.Params)
+		w.Write([]byte(`{"jsonrpc":"2.0","result":{"itemids":[]},"id":1}`))
+	})
+	api.ChunkSize = 2
+
+	if err := api.HistoryClear([]string{"1", "2", "3"}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := [][]string{{"1", "2"}, {"3"}}; !reflect.DeepEqual(requests, expected) {
+		t.Errorf("Requests = %v, expected %v", requests, expected)
+	}
+}
+
+func TestHistoryPush(t *testing.T) {
+	var params []map[string]interface{}
+	api := testFakeAPI(t, "7.0.0", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Params []map[string]interface{} `json:"params"`
+		}
+		testDecodeRequest(r, &req)
+		params = req.Params
+		w.Write([]byte(`{"jsonrpc":"2.0","result":{"response":"success","data":[{"itemid":"1"},{"error":"No permissions to referred object or it does not exist."}]},"id":1}`))
+	})
+
+	res, err := api.HistoryPush([]zapi.HistoryValue{
+		{ItemID: "1", Value: "42", Clock: time.Unix(1700000000, 5)},
+		{Host: "server", Key: "trap", Value: "down"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 2 || res[0].ItemID != "1" || res[0].Error != "" || res[1].Error == "" {
+		t.Errorf("Unexpected results %#v", res)
+	}
+	if len(params) != 2 || params[0]["itemid"] != "1" || params[0]["clock"] != float64(1700000000) || params[0]["ns"] != float64(5) ||
+		params[1]["host"] != "server" || params[1]["key"] != "trap" || params[1]["clock"] != nil {
+		t.Errorf("Unexpected params %v", params)
+	}
+
+	api = testFakeAPI(t, "6.4.0", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Unexpected request")
+	})
+	if _, err = api.HistoryPush([]zapi.HistoryValue{{ItemID: "1", Value: "42"}}); !errors.Is(err, zapi.ErrVersionUnsupported) {
+		t.Errorf("Expected ErrVersionUnsupported, got %v", err)
+	}
+}
@@ -1,5 +1,7 @@
 package zabbix
 
+import "context"
+
 type (
 	// AvailableType (readonly) Availability of Zabbix agent
 	// see "available" in: https://www.zabbix.com/documentation/3.2/manual/api/reference/host/object
@@ -52,96 +54,103 @@ type Hosts []Host
 // HostsGet Wrapper for host.get
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/host/get
 func (api *API) HostsGet(params Params) (res Hosts, err error) {
-	if _, present := params["output"]; !present {
-		params["output"] = "extend"
-	}
-	err = api.CallWithErrorParse("host.get", params, &res)
-	return
+	return api.HostsGetContext(context.Background(), params)
+}
+
+// HostsGetContext is like HostsGet but uses ctx for the underlying requests.
+func (api *API) HostsGetContext(ctx context.Context, params Params) (res Hosts, err error) {
+	return NewRepository[Host](api).Get(ctx, params)
+}
+
+// HostsIter Returns a Stream of the hosts matching params, decoded one at a time.
+// Use it instead of HostsGet for results too large to hold in memory.
+func (api *API) HostsIter(ctx context.Context, params Params) *Stream[Host] {
+	return NewRepository[Host](api).Iter(ctx, params)
 }
 
 // HostsGetByHostGroupIds Gets hosts by host group Ids.
 func (api *API) HostsGetByHostGroupIds(ids []string) (res Hosts, err error) {
-	return api.HostsGet(Params{"groupids": ids})
+	return api.HostsGetByHostGroupIdsContext(context.Background(), ids)
+}
+
+// HostsGetByHostGroupIdsContext is like HostsGetByHostGroupIds but uses ctx for the underlying requests.
+func (api *API) HostsGetByHostGroupIdsContext(ctx context.Context, ids []string) (res Hosts, err error) {
+	return api.HostsGetContext(ctx, Params{"groupids": ids})
 }
 
 // HostsGetByHostGroups Gets hosts by host groups.
 func (api *API) HostsGetByHostGroups(hostGroups HostGroups) (res Hosts, err error) {
+	return api.HostsGetByHostGroupsContext(context.Background(), hostGroups)
+}
+
+// HostsGetByHostGroupsContext is like HostsGetByHostGroups but uses ctx for the underlying requests.
+func (api *API) HostsGetByHostGroupsContext(ctx context.Context, hostGroups HostGroups) (res Hosts, err error) {
 	ids := make([]string, len(hostGroups))
 	for i, id := range hostGroups {
 		ids[i] = id.GroupID
 	}
-	return api.HostsGetByHostGroupIds(ids)
+	return api.HostsGetByHostGroupIdsContext(ctx, ids)
 }
 
 // HostGetByID Gets host by Id only if there is exactly 1 matching host.
 func (api *API) HostGetByID(id string) (res *Host, err error) {
-	hosts, err := api.HostsGet(Params{"hostids": id})
-	if err != nil {
-		return
-	}
+	return api.HostGetByIDContext(context.Background(), id)
+}
 
-	if len(hosts) == 1 {
-		res = &hosts[0]
-	} else {
-		e := ExpectedOneResult(len(hosts))
-		err = &e
-	}
-	return
+// HostGetByIDContext is like HostGetByID but uses ctx for the underlying requests.
+func (api *API) HostGetByIDContext(ctx context.Context, id string) (res *Host, err error) {
+	return NewRepository[Host](api).GetByID(ctx, id)
 }
 
 // HostGetByHost Gets host by Host only if there is exactly 1 matching host.
 func (api *API) HostGetByHost(host string) (res *Host, err error) {
-	hosts, err := api.HostsGet(Params{"filter": map[string]string{"host": host}})
-	if err != nil {
-		return
-	}
+	return api.HostGetByHostContext(context.Background(), host)
+}
 
-	if len(hosts) == 1 {
-		res = &hosts[0]
-	} else {
-		e := ExpectedOneResult(len(hosts))
-		err = &e
-	}
-	return
+// HostGetByHostContext is like HostGetByHost but uses ctx for the underlying requests.
+func (api *API) HostGetByHostContext(ctx context.Context, host string) (res *Host, err error) {
+	return NewRepository[Host](api).GetOne(ctx, Params{"filter": map[string]string{"host": host}})
 }
 
 // HostsCreate Wrapper for host.create
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/host/create
 func (api *API) HostsCreate(hosts Hosts) (err error) {
-	response, err := api.CallWithError("host.create", hosts)
-	if err != nil {
-		return
-	}
+	return api.HostsCreateContext(context.Background(), hosts)
+}
 
-	result := response.Result.(map[string]interface{})
-	hostids := result["hostids"].([]interface{})
-	for i, id := range hostids {
-		hosts[i].HostID = id.(string)
-	}
-	return
+// HostsCreateContext is like HostsCreate but uses ctx for the underlying requests.
+func (api *API) HostsCreateContext(ctx context.Context, hosts Hosts) (err error) {
+	return NewRepository[Host](api).Create(ctx, hosts)
 }
 
 // HostsUpdate Wrapper for host.update
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/host/update
 func (api *API) HostsUpdate(hosts Hosts) (err error) {
-	_, err = api.CallWithError("host.update", hosts)
-	return
+	return api.HostsUpdateContext(context.Background(), hosts)
+}
+
+// HostsUpdateContext is like HostsUpdate but uses ctx for the underlying requests.
+func (api *API) HostsUpdateContext(ctx context.Context, hosts Hosts) (err error) {
+	return NewRepository[Host](api).Update(ctx, hosts)
 }
 
 // HostsDelete Wrapper for host.delete
 // Cleans HostId in all hosts elements if call succeed.
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/host/delete
 func (api *API) HostsDelete(hosts Hosts) (err error) {
+	return api.HostsDeleteContext(context.Background(), hosts)
+}
+
+// HostsDeleteContext is like HostsDelete but uses ctx for the underlying requests.
+func (api *API) HostsDeleteContext(ctx context.Context, hosts Hosts) (err error) {
 	ids := make([]string, len(hosts))
 	for i, host := range hosts {
 		ids[i] = host.HostID
 	}
 
-	err = api.HostsDeleteByIds(ids)
-	if err == nil {
-		for i := range hosts {
-			hosts[i].HostID = ""
-		}
+	err = api.HostsDeleteByIdsContext(ctx, ids)
+	for i := 0; i < chunkDone(len(hosts), err); i++ {
+		hosts[i].HostID = ""
 	}
 	return
 }
@@ -149,7 +158,18 @@ func (api *API) HostsDelete(hosts Hosts) (err error) {
 // HostsDeleteByIds Wrapper for host.delete
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/host/delete
 func (api *API) HostsDeleteByIds(ids []string) (err error) {
-	response, err := api.CallWithError("host.delete", ids)
+	return api.HostsDeleteByIdsContext(context.Background(), ids)
+}
+
+// HostsDeleteByIdsContext is like HostsDeleteByIds but uses ctx for the underlying requests.
+func (api *API) HostsDeleteByIdsContext(ctx context.Context, ids []string) (err error) {
+	return api.eachChunk(len(ids), func(lo, hi int) error {
+		return api.hostsDeleteByIds(ctx, ids[lo:hi])
+	})
+}
+
+func (api *API) hostsDeleteByIds(ctx context.Context, ids []string) (err error) {
+	response, err := api.CallWithErrorContext(ctx, "host.delete", ids)
 	if err != nil {
 		if e, ok := err.(*Error); ok && e.Code == -32500 {
 			// Zabbix 2.0 and older use old syntax only with hostid
@@ -157,7 +177,7 @@ func (api *API) HostsDeleteByIds(ids []string) (err error) {
 			for i, id := range ids {
 				hostIds[i] = map[string]string{"hostid": id}
 			}
-			response, err = api.CallWithError("host.delete", hostIds)
+			response, err = api.CallWithErrorContext(ctx, "host.delete", hostIds)
 		}
 	}
 	if err != nil {
@@ -1,5 +1,7 @@
 package zabbix
 
+import "context"
+
 type (
 	// InternalType (readonly) Whether the group is used internally by the system. An internal group cannot be deleted.
 	// see "internal" in https://www.zabbix.com/documentation/3.2/manual/api/reference/hostgroup/object
@@ -35,82 +37,65 @@ type HostGroupIDs []HostGroupID
 // HostGroupsGet Wrapper for hostgroup.get
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/hostgroup/get
 func (api *API) HostGroupsGet(params Params) (res HostGroups, err error) {
-	if _, present := params["output"]; !present {
-		params["output"] = "extend"
-	}
-	err = api.CallWithErrorParse("hostgroup.get", params, &res)
-	return
+	return api.HostGroupsGetContext(context.Background(), params)
+}
+
+// HostGroupsGetContext is like HostGroupsGet but uses ctx for the underlying requests.
+func (api *API) HostGroupsGetContext(ctx context.Context, params Params) (res HostGroups, err error) {
+	return NewRepository[HostGroup](api).Get(ctx, params)
 }
 
 // HostGroupGetByID Gets host group by Id only if there is exactly 1 matching host group.
 func (api *API) HostGroupGetByID(id string) (res *HostGroup, err error) {
-	groups, err := api.HostGroupsGet(Params{"groupids": id})
-	if err != nil {
-		return
-	}
-
-	if len(groups) == 1 {
-		res = &groups[0]
-	} else {
-		e := ExpectedOneResult(len(groups))
-		err = &e
-	}
-	return
+	return api.HostGroupGetByIDContext(context.Background(), id)
+}
+
+// HostGroupGetByIDContext is like HostGroupGetByID but uses ctx for the underlying requests.
+func (api *API) HostGroupGetByIDContext(ctx context.Context, id string) (res *HostGroup, err error) {
+	return NewRepository[HostGroup](api).GetByID(ctx, id)
 }
 
 // HostGroupsCreate Wrapper for hostgroup.create
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/hostgroup/create
 func (api *API) HostGroupsCreate(hostGroups HostGroups) (err error) {
-	response, err := api.CallWithError("hostgroup.create", hostGroups)
-	if err != nil {
-		return
-	}
-
-	result := response.Result.(map[string]interface{})
-	groupids := result["groupids"].([]interface{})
-	for i, id := range groupids {
-		hostGroups[i].GroupID = id.(string)
-	}
-	return
+	return api.HostGroupsCreateContext(context.Background(), hostGroups)
+}
+
+// HostGroupsCreateContext is like HostGroupsCreate but uses ctx for the underlying requests.
+func (api *API) HostGroupsCreateContext(ctx context.Context, hostGroups HostGroups) (err error) {
+	return NewRepository[HostGroup](api).Create(ctx, hostGroups)
 }
 
 // HostGroupsUpdate Wrapper for hostgroup.update
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/hostgroup/update
 func (api *API) HostGroupsUpdate(hostGroups HostGroups) (err error) {
-	_, err = api.CallWithError("hostgroup.update", hostGroups)
-	return
+	return api.HostGroupsUpdateContext(context.Background(), hostGroups)
+}
+
+// HostGroupsUpdateContext is like HostGroupsUpdate but uses ctx for the underlying requests.
+func (api *API) HostGroupsUpdateContext(ctx context.Context, hostGroups HostGroups) (err error) {
+	return NewRepository[HostGroup](api).Update(ctx, hostGroups)
 }
 
 // HostGroupsDelete Wrapper for hostgroup.delete
 // Cleans GroupId in all hostGroups elements if call succeed.
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/hostgroup/delete
 func (api *API) HostGroupsDelete(hostGroups HostGroups) (err error) {
-	ids := make([]string, len(hostGroups))
-	for i, group := range hostGroups {
-		ids[i] = group.GroupID
-	}
-
-	err = api.HostGroupsDeleteByIds(ids)
-	if err == nil {
-		for i := range hostGroups {
-			hostGroups[i].GroupID = ""
-		}
-	}
-	return
+	return api.HostGroupsDeleteContext(context.Background(), hostGroups)
+}
+
+// HostGroupsDeleteContext is like HostGroupsDelete but uses ctx for the underlying requests.
+func (api *API) HostGroupsDeleteContext(ctx context.Context, hostGroups HostGroups) (err error) {
+	return NewRepository[HostGroup](api).Delete(ctx, hostGroups)
 }
 
 // HostGroupsDeleteByIds Wrapper for hostgroup.delete
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/hostgroup/delete
 func (api *API) HostGroupsDeleteByIds(ids []string) (err error) {
-	response, err := api.CallWithError("hostgroup.delete", ids)
-	if err != nil {
-		return
-	}
-
-	result := response.Result.(map[string]interface{})
-	groupids := result["groupids"].([]interface{})
-	if len(ids) != len(groupids) {
-		err = &ExpectedMore{len(ids), len(groupids)}
-	}
-	return
+	return api.HostGroupsDeleteByIdsContext(context.Background(), ids)
+}
+
+// HostGroupsDeleteByIdsContext is like HostGroupsDeleteByIds but uses ctx for the underlying requests.
+func (api *API) HostGroupsDeleteByIdsContext(ctx context.Context, ids []string) (err error) {
+	return NewRepository[HostGroup](api).DeleteByIDs(ctx, ids)
 }
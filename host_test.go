@@ -179,6 +179,7 @@ func TestHosts(t *testing.T) {
 	}
 	macro2 := hosts[0].UserMacros[0]
 	macro.HostID = hosts[0].HostID
+	macro.MacroID = macro2.MacroID
 	if !reflect.DeepEqual(macro, macro2) {
 		t.Errorf("UserMacros are not equal:\n%#v\n%#v", macro, macro2)
 	}
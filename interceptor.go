@@ -0,0 +1,40 @@
+package zabbix
+
+import "context"
+
+// CallFunc performs the API call of method with params and returns the raw JSON-RPC response.
+// err is something network or marshaling related, API errors are part of the response.
+type CallFunc func(ctx context.Context, method string, params interface{}) (response []byte, err error)
+
+// Interceptor wraps a CallFunc to observe, change or short-circuit API calls.
+// An interceptor may call next with other arguments, inspect or replace its response and error,
+// or return a response without calling next at all.
+//
+// For streamed calls, like Repository.Iter and Pager, next returns a nil response and a nil error
+// when the result is being streamed, as the body is read by the Stream and not by next.
+// An API error is still returned as the response. A response returned by an interceptor replaces
+// the stream, so interceptors logging, validating or caching responses must handle nil ones.
+//
+//	api.Use(func(next zabbix.CallFunc) zabbix.CallFunc {
+//		return func(ctx context.Context, method string, params interface{}) ([]byte, error) {
+//			start := time.Now()
+//			response, err := next(ctx, method, params)
+//			log.Printf("%s took %s", method, time.Since(start))
+//			return response, err
+//		}
+//	})
+type Interceptor func(next CallFunc) CallFunc
+
+// Use Appends interceptors to the chain wrapping every API call, the first one being the outermost.
+// Interceptors wrap retries and session re-login, so they see one final response per call.
+//
+// Each call of a Batch goes through the chain on its own, concurrently, and the calls reaching the innermost
+// CallFunc are sent together in one request. The request is sent once every call reached it or returned,
+// or once the others stayed in the chain a few milliseconds, like a call waiting for another one.
+// Calls reaching the innermost CallFunc after that, or sent again by the chain, are sent alone.
+// Streamed calls go through the chain too, see Interceptor for their nil responses.
+func (api *API) Use(interceptors ...Interceptor) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.interceptors = append(api.interceptors[:len(api.interceptors):len(api.interceptors)], interceptors...)
+}
@@ -0,0 +1,288 @@
+package zabbix_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	zapi "github.com/claranet/go-zabbix-api"
+)
+
+func TestInterceptors(t *testing.T) {
+	var requests int
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"jsonrpc":"2.0","result":[{"hostid":"1"}],"id":1}`))
+	})
+
+	var calls []string
+	api.Use(func(next zapi.CallFunc) zapi.CallFunc {
+		return func(ctx context.Context, method string, params interface{}) ([]byte, error) {
+			calls = append(calls, "outer "+method)
+			return next(ctx, method, params)
+		}
+	}, func(next zapi.CallFunc) zapi.CallFunc {
+		return func(ctx context.Context, method string, params interface{}) ([]byte, error) {
+			calls = append(calls, "inner "+method)
+			if method == "item.get" {
+				return []byte(`{"jsonrpc":"2.0","result":[{"itemid":"42"}],"id":1}`), nil
+			}
+			return next(ctx, method, params)
+		}
+	})
+
+	hosts, err := api.HostsGet(zapi.Params{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hosts) != 1 || hosts[0].HostID != "1" {
+		t.Errorf("Bad hosts: %#v", hosts)
+	}
+
+	items, err := api.ItemsGet(zapi.Params{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].ItemID != "42" {
+		t.Errorf("Bad items: %#v", items)
+	}
+
+	expected := []string{"outer host.get", "inner host.get", "outer item.get", "inner item.get"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("Bad interceptor calls: %v", calls)
+	}
+	if requests != 1 {
+		t.Errorf("Expected item.get to be short-circuited, got %d requests", requests)
+	}
+}
+
+func TestInterceptorsBatch(t *testing.T) {
+	var methods []string
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		var requests []struct {
+			Method string `json:"method"`
+			ID     int32  `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&requests)
+		w.Write([]byte("["))
+		for i, req := range requests {
+			methods = append(methods, req.Method)
+			if i > 0 {
+				w.Write([]byte(","))
+			}
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","result":[{"hostid":"1","itemid":"1"}],"id":%d}`, req.ID)
+		}
+		w.Write([]byte("]"))
+	})
+
+	var mu sync.Mutex
+	var calls []string
+	api.Use(func(next zapi.CallFunc) zapi.CallFunc {
+		return func(ctx context.Context, method string, params interface{}) ([]byte, error) {
+			mu.Lock()
+			calls = append(calls, method)
+			mu.Unlock()
+			if method == "item.get" {
+				return []byte(`{"jsonrpc":"2.0","result":[{"itemid":"42"}],"id":1}`), nil
+			}
+			return next(ctx, method, params)
+		}
+	})
+
+	var hosts zapi.Hosts
+	var items zapi.Items
+	var groups zapi.HostGroups
+	b := api.NewBatch()
+	b.Add("host.get", zapi.Params{}, &hosts)
+	b.Add("item.get", zapi.Params{}, &items)
+	b.Add("hostgroup.get", zapi.Params{}, &groups)
+	if err := b.Do(); err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(calls)
+	if !reflect.DeepEqual(calls, []string{"host.get", "hostgroup.get", "item.get"}) {
+		t.Errorf("Expected the interceptor to see every batched call, got %v", calls)
+	}
+	if !reflect.DeepEqual(methods, []string{"host.get", "hostgroup.get"}) {
+		t.Errorf("Expected the other calls in one batch, got %v", methods)
+	}
+	if len(hosts) != 1 || len(groups) != 1 || len(items) != 1 || items[0].ItemID != "42" {
+		t.Errorf("Bad results %#v %#v %#v", hosts, groups, items)
+	}
+}
+
+func TestInterceptorsStream(t *testing.T) {
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		if testRPCMethod(r) == "trigger.get" {
+			fmt.Fprint(w, `{"jsonrpc":"2.0","error":{"code":-32602,"message":"Invalid params.","data":"No permissions."},"id":1}`)
+			return
+		}
+		fmt.Fprint(w, `{"jsonrpc":"2.0","result":[{"itemid":"1"},{"itemid":"2"}],"id":1}`)
+	})
+
+	var calls []string
+	var responses [][]byte
+	var errs []error
+	api.Use(func(next zapi.CallFunc) zapi.CallFunc {
+		return func(ctx context.Context, method string, params interface{}) ([]byte, error) {
+			calls = append(calls, method)
+			if method == "host.get" {
+				return []byte(`{"jsonrpc":"2.0","result":[{"hostid":"42"}],"id":1}`), nil
+			}
+			response, err := next(ctx, method, params)
+			responses = append(responses, response)
+			errs = append(errs, err)
+			return response, err
+		}
+	})
+
+	items := api.ItemsIter(context.Background(), zapi.Params{})
+	defer items.Close()
+	n := 0
+	for items.Next() {
+		n++
+	}
+	if err := items.Err(); err != nil || n != 2 {
+		t.Errorf("Expected 2 streamed items, got %d: %v", n, err)
+	}
+	if len(responses) != 1 || responses[0] != nil || errs[0] != nil {
+		t.Errorf("Expected a nil response and error for the streamed result, got %q %v", responses, errs)
+	}
+
+	triggers := zapi.NewRepository[zapi.Trigger](api).Iter(context.Background(), zapi.Params{})
+	defer triggers.Close()
+	if triggers.Next() || triggers.Err() == nil {
+		t.Errorf("Expected the API error, got %v", triggers.Err())
+	}
+	if len(responses) != 2 || !strings.Contains(string(responses[1]), "No permissions.") || errs[1] != nil {
+		t.Errorf("Expected the API error response, got %q %v", responses, errs)
+	}
+
+	hosts := zapi.NewRepository[zapi.Host](api).Iter(context.Background(), zapi.Params{})
+	defer hosts.Close()
+	if !hosts.Next() || hosts.Value().HostID != "42" || hosts.Next() {
+		t.Errorf("Expected the host of the interceptor, got %v", hosts.Err())
+	}
+	if !reflect.DeepEqual(calls, []string{"item.get", "trigger.get", "host.get"}) {
+		t.Errorf("Expected the interceptor to see the streamed calls, got %v", calls)
+	}
+}
+
+// testSingleflight returns an interceptor making identical concurrent calls share the response of the first one.
+func testSingleflight() zapi.Interceptor {
+	type flight struct {
+		done     chan struct{}
+		response []byte
+		err      error
+	}
+	var mu sync.Mutex
+	flights := map[string]*flight{}
+	return func(next zapi.CallFunc) zapi.CallFunc {
+		return func(ctx context.Context, method string, params interface{}) ([]byte, error) {
+			b, _ := json.Marshal(params)
+			key := method + string(b)
+			mu.Lock()
+			if f, ok := flights[key]; ok {
+				mu.Unlock()
+				<-f.done
+				return f.response, f.err
+			}
+			f := &flight{done: make(chan struct{})}
+			flights[key] = f
+			mu.Unlock()
+
+			f.response, f.err = next(ctx, method, params)
+			close(f.done)
+			mu.Lock()
+			delete(flights, key)
+			mu.Unlock()
+			return f.response, f.err
+		}
+	}
+}
+
+func TestInterceptorsBatchWaitingCall(t *testing.T) {
+	var batches [][]string
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		var requests []struct {
+			Method string `json:"method"`
+			ID     int32  `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&requests)
+		var methods []string
+		w.Write([]byte("["))
+		for i, req := range requests {
+			methods = append(methods, req.Method)
+			if i > 0 {
+				w.Write([]byte(","))
+			}
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","result":[{"hostid":"1"}],"id":%d}`, req.ID)
+		}
+		w.Write([]byte("]"))
+		batches = append(batches, methods)
+	})
+	api.Use(testSingleflight())
+
+	var first, second zapi.Hosts
+	b := api.NewBatch()
+	b.Add("host.get", zapi.Params{}, &first)
+	b.Add("host.get", zapi.Params{}, &second)
+	done := make(chan error, 1)
+	go func() { done <- b.Do() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("batch deadlocked")
+	}
+
+	if !reflect.DeepEqual(batches, [][]string{{"host.get"}}) {
+		t.Errorf("Expected one batch of the first call, got %v", batches)
+	}
+	if len(first) != 1 || len(second) != 1 {
+		t.Errorf("Bad results %#v %#v", first, second)
+	}
+}
+
+func TestInterceptorsBatchCanceled(t *testing.T) {
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	})
+	unblock := make(chan struct{})
+	defer close(unblock)
+	api.Use(func(next zapi.CallFunc) zapi.CallFunc {
+		return func(ctx context.Context, method string, params interface{}) ([]byte, error) {
+			if method == "item.get" {
+				// ignores ctx
+				<-unblock
+			}
+			return next(ctx, method, params)
+		}
+	})
+
+	b := api.NewBatch()
+	b.Add("host.get", zapi.Params{}, nil)
+	b.Add("item.get", zapi.Params{}, nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- b.DoContext(ctx) }()
+	select {
+	case err := <-done:
+		if err != context.DeadlineExceeded {
+			t.Errorf("err = %v, want context.DeadlineExceeded", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("canceling did not release the batch")
+	}
+}
@@ -1,6 +1,7 @@
 package zabbix
 
 import (
+	"context"
 	"fmt"
 )
 
@@ -148,106 +149,96 @@ func (items Items) ByKey() (res map[string]Item) {
 // ItemsGet Wrapper for item.get
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/item/get
 func (api *API) ItemsGet(params Params) (res Items, err error) {
-	if _, present := params["output"]; !present {
-		params["output"] = "extend"
-	}
-	err = api.CallWithErrorParse("item.get", params, &res)
-	return
+	return api.ItemsGetContext(context.Background(), params)
+}
+
+// ItemsGetContext is like ItemsGet but uses ctx for the underlying requests.
+func (api *API) ItemsGetContext(ctx context.Context, params Params) (res Items, err error) {
+	return NewRepository[Item](api).Get(ctx, params)
+}
+
+// ItemsIter Returns a Stream of the items matching params, decoded one at a time.
+// Use it instead of ItemsGet for results too large to hold in memory.
+func (api *API) ItemsIter(ctx context.Context, params Params) *Stream[Item] {
+	return NewRepository[Item](api).Iter(ctx, params)
 }
 
 // ItemGetByID Gets item by Id only if there is exactly 1 matching host.
 func (api *API) ItemGetByID(id string) (res *Item, err error) {
-	items, err := api.ItemsGet(Params{"itemids": id})
-	if err != nil {
-		return
-	}
+	return api.ItemGetByIDContext(context.Background(), id)
+}
 
-	if len(items) != 1 {
-		e := ExpectedOneResult(len(items))
-		err = &e
-		return
-	}
-	res = &items[0]
-	return
+// ItemGetByIDContext is like ItemGetByID but uses ctx for the underlying requests.
+func (api *API) ItemGetByIDContext(ctx context.Context, id string) (res *Item, err error) {
+	return NewRepository[Item](api).GetByID(ctx, id)
 }
 
 // ItemsGetByApplicationID Gets items by application Id.
 func (api *API) ItemsGetByApplicationID(id string) (res Items, err error) {
-	return api.ItemsGet(Params{"applicationids": id})
+	return api.ItemsGetByApplicationIDContext(context.Background(), id)
+}
+
+// ItemsGetByApplicationIDContext is like ItemsGetByApplicationID but uses ctx for the underlying requests.
+func (api *API) ItemsGetByApplicationIDContext(ctx context.Context, id string) (res Items, err error) {
+	return api.ItemsGetContext(ctx, Params{"applicationids": id})
 }
 
 // ItemsCreate Wrapper for item.create
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/item/create
 func (api *API) ItemsCreate(items Items) (err error) {
-	response, err := api.CallWithError("item.create", items)
-	if err != nil {
-		return
-	}
+	return api.ItemsCreateContext(context.Background(), items)
+}
 
-	result := response.Result.(map[string]interface{})
-	itemids := result["itemids"].([]interface{})
-	for i, id := range itemids {
-		items[i].ItemID = id.(string)
-	}
-	return
+// ItemsCreateContext is like ItemsCreate but uses ctx for the underlying requests.
+func (api *API) ItemsCreateContext(ctx context.Context, items Items) (err error) {
+	return NewRepository[Item](api).Create(ctx, items)
 }
 
 // ItemsUpdate Wrapper for item.update
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/item/update
 func (api *API) ItemsUpdate(items Items) (err error) {
-	_, err = api.CallWithError("item.update", items)
-	return
+	return api.ItemsUpdateContext(context.Background(), items)
+}
+
+// ItemsUpdateContext is like ItemsUpdate but uses ctx for the underlying requests.
+func (api *API) ItemsUpdateContext(ctx context.Context, items Items) (err error) {
+	return NewRepository[Item](api).Update(ctx, items)
 }
 
 // ItemsDelete Wrapper for item.delete
 // Cleans ItemId in all items elements if call succeed.
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/item/delete
 func (api *API) ItemsDelete(items Items) (err error) {
-	ids := make([]string, len(items))
-	for i, item := range items {
-		ids[i] = item.ItemID
-	}
+	return api.ItemsDeleteContext(context.Background(), items)
+}
 
-	err = api.ItemsDeleteByIds(ids)
-	if err == nil {
-		for i := range items {
-			items[i].ItemID = ""
-		}
-	}
-	return
+// ItemsDeleteContext is like ItemsDelete but uses ctx for the underlying requests.
+func (api *API) ItemsDeleteContext(ctx context.Context, items Items) (err error) {
+	return NewRepository[Item](api).Delete(ctx, items)
 }
 
 // ItemsDeleteByIds Wrapper for item.delete
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/item/delete
 func (api *API) ItemsDeleteByIds(ids []string) (err error) {
-	deleteIds, err := api.ItemsDeleteIDs(ids)
-	if err != nil {
-		return
-	}
-	l := len(deleteIds)
-	if len(ids) != l {
-		err = &ExpectedMore{len(ids), l}
-	}
-	return
+	return api.ItemsDeleteByIdsContext(context.Background(), ids)
+}
+
+// ItemsDeleteByIdsContext is like ItemsDeleteByIds but uses ctx for the underlying requests.
+func (api *API) ItemsDeleteByIdsContext(ctx context.Context, ids []string) (err error) {
+	return NewRepository[Item](api).DeleteByIDs(ctx, ids)
 }
 
 // ItemsDeleteIDs Wrapper for item.delete
 // Delete the item and return the id of the deleted item
 func (api *API) ItemsDeleteIDs(ids []string) (itemids []interface{}, err error) {
-	response, err := api.CallWithError("item.delete", ids)
-	if err != nil {
-		return
-	}
+	return api.ItemsDeleteIDsContext(context.Background(), ids)
+}
 
-	result := response.Result.(map[string]interface{})
-	itemids1, ok := result["itemids"].([]interface{})
-	if !ok {
-		itemids2 := result["itemids"].(map[string]interface{})
-		for _, id := range itemids2 {
-			itemids = append(itemids, id)
-		}
-	} else {
-		itemids = itemids1
+// ItemsDeleteIDsContext is like ItemsDeleteIDs but uses ctx for the underlying requests.
+func (api *API) ItemsDeleteIDsContext(ctx context.Context, ids []string) (itemids []interface{}, err error) {
+	deleted, err := NewRepository[Item](api).deleteIDs(ctx, ids)
+	for _, id := range deleted {
+		itemids = append(itemids, id)
 	}
 	return
 }
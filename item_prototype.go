@@ -1,5 +1,7 @@
 package zabbix
 
+import "context"
+
 // ItemPrototype represent Zabbix item prototype object
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/itemprototype/object
 type ItemPrototype struct {
@@ -53,94 +55,81 @@ type ItemPrototypes []ItemPrototype
 // ItemPrototypesGet Wrapper for item.get
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/itemprototype/get
 func (api *API) ItemPrototypesGet(params Params) (res ItemPrototypes, err error) {
-	if _, present := params["output"]; !present {
-		params["output"] = "extend"
-	}
-	err = api.CallWithErrorParse("itemprototype.get", params, &res)
-	return
+	return api.ItemPrototypesGetContext(context.Background(), params)
+}
+
+// ItemPrototypesGetContext is like ItemPrototypesGet but uses ctx for the underlying requests.
+func (api *API) ItemPrototypesGetContext(ctx context.Context, params Params) (res ItemPrototypes, err error) {
+	return NewRepository[ItemPrototype](api).Get(ctx, params)
 }
 
 // ItemPrototypeGetByID Gets item by Id only if there is exactly 1 matching item.
 func (api *API) ItemPrototypeGetByID(id string) (res *ItemPrototype, err error) {
-	items, err := api.ItemPrototypesGet(Params{"itemids": id})
-	if err != nil {
-		return
-	}
+	return api.ItemPrototypeGetByIDContext(context.Background(), id)
+}
 
-	if len(items) != 1 {
-		e := ExpectedOneResult(len(items))
-		err = &e
-		return
-	}
-	res = &items[0]
-	return
+// ItemPrototypeGetByIDContext is like ItemPrototypeGetByID but uses ctx for the underlying requests.
+func (api *API) ItemPrototypeGetByIDContext(ctx context.Context, id string) (res *ItemPrototype, err error) {
+	return NewRepository[ItemPrototype](api).GetByID(ctx, id)
 }
 
 // ItemPrototypesCreate Wrapper for item.create
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/itemprototype/create
 func (api *API) ItemPrototypesCreate(items ItemPrototypes) (err error) {
-	response, err := api.CallWithError("itemprototype.create", items)
-	if err != nil {
-		return
-	}
+	return api.ItemPrototypesCreateContext(context.Background(), items)
+}
 
-	result := response.Result.(map[string]interface{})
-	itemids := result["itemids"].([]interface{})
-	for i, id := range itemids {
-		items[i].ItemID = id.(string)
-	}
-	return
+// ItemPrototypesCreateContext is like ItemPrototypesCreate but uses ctx for the underlying requests.
+func (api *API) ItemPrototypesCreateContext(ctx context.Context, items ItemPrototypes) (err error) {
+	return NewRepository[ItemPrototype](api).Create(ctx, items)
 }
 
 // ItemPrototypesUpdate Wrapper for item.update
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/itemprototype/update
 func (api *API) ItemPrototypesUpdate(items ItemPrototypes) (err error) {
-	_, err = api.CallWithError("itemprototype.update", items)
-	return
+	return api.ItemPrototypesUpdateContext(context.Background(), items)
+}
+
+// ItemPrototypesUpdateContext is like ItemPrototypesUpdate but uses ctx for the underlying requests.
+func (api *API) ItemPrototypesUpdateContext(ctx context.Context, items ItemPrototypes) (err error) {
+	return NewRepository[ItemPrototype](api).Update(ctx, items)
 }
 
 // ItemPrototypesDelete Wrapper for item.delete
 // Cleans ItemId in all items elements if call succeed.
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/itemprototype/delete
 func (api *API) ItemPrototypesDelete(items ItemPrototypes) (err error) {
-	ids := make([]string, len(items))
-	for i, item := range items {
-		ids[i] = item.ItemID
-	}
+	return api.ItemPrototypesDeleteContext(context.Background(), items)
+}
 
-	err = api.ItemPrototypesDeleteByIds(ids)
-	if err == nil {
-		for i := range items {
-			items[i].ItemID = ""
-		}
-	}
-	return
+// ItemPrototypesDeleteContext is like ItemPrototypesDelete but uses ctx for the underlying requests.
+func (api *API) ItemPrototypesDeleteContext(ctx context.Context, items ItemPrototypes) (err error) {
+	return NewRepository[ItemPrototype](api).Delete(ctx, items)
 }
 
 // ItemPrototypesDeleteByIds Wrapper for item.delete
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/itemprototype/delete
 func (api *API) ItemPrototypesDeleteByIds(ids []string) (err error) {
-	itemids1, err := api.ItemPrototypesDeleteIDs(ids)
-	if err != nil {
-		return
-	}
+	return api.ItemPrototypesDeleteByIdsContext(context.Background(), ids)
+}
 
-	if len(itemids1) != len(ids) {
-		err = &ExpectedMore{len(ids), len(itemids1)}
-	}
-	return
+// ItemPrototypesDeleteByIdsContext is like ItemPrototypesDeleteByIds but uses ctx for the underlying requests.
+func (api *API) ItemPrototypesDeleteByIdsContext(ctx context.Context, ids []string) (err error) {
+	return NewRepository[ItemPrototype](api).DeleteByIDs(ctx, ids)
 }
 
 // ItemPrototypesDeleteIDs Wrapper for item.delete
 // Delete the item prototype and return the id of the deleted item prototype
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/itemprototype/delete
 func (api *API) ItemPrototypesDeleteIDs(ids []string) (itemids1 []interface{}, err error) {
-	response, err := api.CallWithError("itemprototype.delete", ids)
-	if err != nil {
-		return
-	}
+	return api.ItemPrototypesDeleteIDsContext(context.Background(), ids)
+}
 
-	result := response.Result.(map[string]interface{})
-	itemids1 = result["prototypeids"].([]interface{})
+// ItemPrototypesDeleteIDsContext is like ItemPrototypesDeleteIDs but uses ctx for the underlying requests.
+func (api *API) ItemPrototypesDeleteIDsContext(ctx context.Context, ids []string) (itemids1 []interface{}, err error) {
+	deleted, err := NewRepository[ItemPrototype](api).deleteIDs(ctx, ids)
+	for _, id := range deleted {
+		itemids1 = append(itemids1, id)
+	}
 	return
 }
@@ -0,0 +1,148 @@
+package zabbix
+
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Limits caps the HTTP requests sent to the server, to keep the frontend responsive
+// while jobs run. Every attempt of a call counts, and a batch counts as one request.
+// The zero value sets no limit.
+type Limits struct {
+	// Rate is the number of requests per second, 0 means no limit.
+	Rate float64
+	// Burst is the number of requests sent at once before Rate applies, 1 when lower.
+	Burst int
+	// MaxInFlight is the number of requests waiting for their response at once, 0 means no limit.
+	// An open Stream keeps its request in flight until it is closed.
+	MaxInFlight int
+}
+
+// limiter enforces Limits with a token bucket and a semaphore.
+type limiter struct {
+	rate    float64
+	burst   float64
+	slots   chan struct{}
+	waiting int32
+
+	mu     sync.Mutex // guards tokens and last
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(l Limits) *limiter {
+	if l.Rate <= 0 && l.MaxInFlight <= 0 {
+		return nil
+	}
+	lim := &limiter{rate: l.Rate, burst: float64(l.Burst), last: time.Now()}
+	if lim.burst < 1 {
+		lim.burst = 1
+	}
+	lim.tokens = lim.burst
+	if l.MaxInFlight > 0 {
+		lim.slots = make(chan struct{}, l.MaxInFlight)
+	}
+	return lim
+}
+
+// acquire waits for a token and an in-flight slot, the slot is freed by release.
+func (l *limiter) acquire(ctx context.Context) (release func(), err error) {
+	atomic.AddInt32(&l.waiting, 1)
+	defer atomic.AddInt32(&l.waiting, -1)
+
+	if err = l.wait(ctx); err != nil {
+		return
+	}
+	if l.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+	case <-ctx.Done():
+		// The request is not sent, so its token goes back to the bucket
+		l.refund()
+		return nil, ctx.Err()
+	}
+	var once sync.Once
+	return func() { once.Do(func() { <-l.slots }) }, nil
+}
+
+// wait takes a token from the bucket, waiting for it to refill if needed.
+func (l *limiter) wait(ctx context.Context) error {
+	if l.rate <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	// The token is reserved now, so that waiting requests are served in order
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if err := sleepContext(ctx, delay); err != nil {
+		l.refund()
+		return err
+	}
+	return nil
+}
+
+// refund gives back the token taken by wait for a request which is not sent.
+func (l *limiter) refund() {
+	if l.rate <= 0 {
+		return
+	}
+	l.mu.Lock()
+	l.tokens++
+	l.mu.Unlock()
+}
+
+// SetLimits Sets the limits of read-only requests (*.get and apiinfo.version)
+// and of the other requests, which modify data. Batches of read-only calls are reads.
+func (api *API) SetLimits(read, write Limits) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	api.readLimiter = newLimiter(read)
+	api.writeLimiter = newLimiter(write)
+}
+
+// QueueDepth Returns the number of read and write requests waiting for their limits, see SetLimits.
+func (api *API) QueueDepth() (reads, writes int) {
+	api.mu.RLock()
+	defer api.mu.RUnlock()
+	if api.readLimiter != nil {
+		reads = int(atomic.LoadInt32(&api.readLimiter.waiting))
+	}
+	if api.writeLimiter != nil {
+		writes = int(atomic.LoadInt32(&api.writeLimiter.waiting))
+	}
+	return
+}
+
+// limiterFor returns the limiter of ex, nil when there is none.
+func (api *API) limiterFor(ex *exchange) *limiter {
+	api.mu.RLock()
+	defer api.mu.RUnlock()
+	for _, method := range ex.methods {
+		if !isReadOnlyMethod(method) {
+			return api.writeLimiter
+		}
+	}
+	return api.readLimiter
+}
+
+// releaseBody frees the in-flight slot of a response when its body is closed.
+type releaseBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	defer b.release()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,161 @@
+package zabbix_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	zapi "github.com/claranet/go-zabbix-api"
+)
+
+func TestLimitsMaxInFlight(t *testing.T) {
+	release := make(chan struct{})
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		<-release
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		fmt.Fprint(w, `{"jsonrpc":"2.0","result":[],"id":1}`)
+	})
+	api.SetLimits(zapi.Limits{MaxInFlight: 2}, zapi.Limits{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := api.HostsGet(zapi.Params{}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		reads, writes := api.QueueDepth()
+		mu.Lock()
+		n := inFlight
+		mu.Unlock()
+		if reads == 3 && writes == 0 && n == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("QueueDepth = %d, %d, want 3, 0", reads, writes)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	if maxInFlight != 2 {
+		t.Errorf("max requests in flight = %d, want 2", maxInFlight)
+	}
+	if reads, writes := api.QueueDepth(); reads != 0 || writes != 0 {
+		t.Errorf("QueueDepth = %d, %d after the requests", reads, writes)
+	}
+}
+
+func TestLimitsRate(t *testing.T) {
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		if testRPCMethod(r) == "host.delete" {
+			fmt.Fprint(w, `{"jsonrpc":"2.0","result":{"hostids":["1"]},"id":1}`)
+			return
+		}
+		fmt.Fprint(w, `{"jsonrpc":"2.0","result":[],"id":1}`)
+	})
+	api.SetLimits(zapi.Limits{}, zapi.Limits{Rate: 20, Burst: 2})
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if _, err := api.HostsGet(zapi.Params{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("reads took %s, they should not be limited", elapsed)
+	}
+
+	start = time.Now()
+	for i := 0; i < 6; i++ {
+		if err := api.HostsDeleteByIds([]string{"1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 2 writes are sent at once, the 4 others every 50ms
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Errorf("writes took %s, want at least 200ms", elapsed)
+	}
+}
+
+func TestLimitsContextCanceled(t *testing.T) {
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"jsonrpc":"2.0","result":[],"id":1}`)
+	})
+	api.SetLimits(zapi.Limits{Rate: 0.1}, zapi.Limits{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := api.HostsGetContext(ctx, zapi.Params{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.HostsGetContext(ctx, zapi.Params{}); err != context.DeadlineExceeded {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestLimitsCanceledWaitingForSlot(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		first := false
+		once.Do(func() { first = true })
+		if first {
+			close(started)
+			<-release
+		}
+		fmt.Fprint(w, `{"jsonrpc":"2.0","result":[],"id":1}`)
+	})
+	api.SetLimits(zapi.Limits{Rate: 1, Burst: 2, MaxInFlight: 1}, zapi.Limits{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if _, err := api.HostsGet(zapi.Params{}); err != nil {
+			t.Error(err)
+		}
+	}()
+	<-started
+
+	// The second call takes the last token then waits for the slot of the first one
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		for reads, _ := api.QueueDepth(); reads == 0; reads, _ = api.QueueDepth() {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+	}()
+	if _, err := api.HostsGetContext(ctx, zapi.Params{}); err != context.Canceled {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	close(release)
+	<-done
+
+	start := time.Now()
+	if _, err := api.HostsGet(zapi.Params{}); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("call took %s, the token of the canceled call was not refunded", elapsed)
+	}
+}
@@ -1,5 +1,7 @@
 package zabbix
 
+import "context"
+
 // LLDRulesFilterCondition represent zabbix low-level discovery rules filter condition(LLD rule file condition) object
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/discoveryrule/object#lld_rule_filter_condition
 type LLDRulesFilterCondition struct {
@@ -65,95 +67,82 @@ type LLDRules []LLDRule
 // DiscoveryRulesGet Wrapper for discoveryrule.get
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/discoveryrule/get
 func (api *API) DiscoveryRulesGet(params Params) (res LLDRules, err error) {
-	if _, present := params["output"]; !present {
-		params["output"] = "extend"
-	}
-	err = api.CallWithErrorParse("discoveryrule.get", params, &res)
-	return
+	return api.DiscoveryRulesGetContext(context.Background(), params)
+}
+
+// DiscoveryRulesGetContext is like DiscoveryRulesGet but uses ctx for the underlying requests.
+func (api *API) DiscoveryRulesGetContext(ctx context.Context, params Params) (res LLDRules, err error) {
+	return NewRepository[LLDRule](api).Get(ctx, params)
 }
 
 // DiscoveryRulesGetByID Gets discovery rule by id only if there is exactly 1 matching discovery rule.
 func (api *API) DiscoveryRulesGetByID(id string) (res *LLDRule, err error) {
-	result, err := api.DiscoveryRulesGet(Params{"itemids": id})
-	if err != nil {
-		return
-	}
+	return api.DiscoveryRulesGetByIDContext(context.Background(), id)
+}
 
-	if len(result) == 1 {
-		res = &result[0]
-	} else {
-		e := ExpectedOneResult(len(result))
-		err = &e
-	}
-	return
+// DiscoveryRulesGetByIDContext is like DiscoveryRulesGetByID but uses ctx for the underlying requests.
+func (api *API) DiscoveryRulesGetByIDContext(ctx context.Context, id string) (res *LLDRule, err error) {
+	return NewRepository[LLDRule](api).GetByID(ctx, id)
 }
 
 // DiscoveryRulesCreate Wrapper for discoveryrule.create
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/discoveryrule/create
 func (api *API) DiscoveryRulesCreate(rules LLDRules) error {
-	result, err := api.CallWithError("discoveryrule.create", rules)
-	if err != nil {
-		return err
-	}
+	return api.DiscoveryRulesCreateContext(context.Background(), rules)
+}
 
-	res := result.Result.(map[string]interface{})
-	ids := res["itemids"].([]interface{})
-	for i, id := range ids {
-		rules[i].ItemID = id.(string)
-	}
-	return nil
+// DiscoveryRulesCreateContext is like DiscoveryRulesCreate but uses ctx for the underlying requests.
+func (api *API) DiscoveryRulesCreateContext(ctx context.Context, rules LLDRules) error {
+	return NewRepository[LLDRule](api).Create(ctx, rules)
 }
 
 // DiscoveryRulesUpdate Wrapper for discoveryrule.update
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/discoveryrule/update
 func (api *API) DiscoveryRulesUpdate(rules LLDRules) error {
-	_, err := api.CallWithError("discoveryrule.update", rules)
-	return err
+	return api.DiscoveryRulesUpdateContext(context.Background(), rules)
+}
+
+// DiscoveryRulesUpdateContext is like DiscoveryRulesUpdate but uses ctx for the underlying requests.
+func (api *API) DiscoveryRulesUpdateContext(ctx context.Context, rules LLDRules) error {
+	return NewRepository[LLDRule](api).Update(ctx, rules)
 }
 
 // DiscoveryRulesDelete Wrapper for discoveryrule.delete
 // Cleans ItemID in all discovery rule element if call succeed.
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/discoveryrule/delete
 func (api *API) DiscoveryRulesDelete(rules LLDRules) (err error) {
-	var ids []string
-	for _, rule := range rules {
-		ids = append(ids, rule.ItemID)
-	}
+	return api.DiscoveryRulesDeleteContext(context.Background(), rules)
+}
 
-	err = api.DiscoveryRulesDeletesByIDs(ids)
-	if err == nil {
-		for i := range rules {
-			rules[i].ItemID = ""
-		}
-	}
-	return
+// DiscoveryRulesDeleteContext is like DiscoveryRulesDelete but uses ctx for the underlying requests.
+func (api *API) DiscoveryRulesDeleteContext(ctx context.Context, rules LLDRules) (err error) {
+	return NewRepository[LLDRule](api).Delete(ctx, rules)
 }
 
 // DiscoveryRulesDeletesByIDs  Wrapper for discorveryrule.delete
 // Delete the discovery rule with the given ids
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/discoveryrule/delete
 func (api *API) DiscoveryRulesDeletesByIDs(ids []string) (err error) {
-	drulsids, err := api.DiscoveryRulesDeletesIDs(ids)
-	if err != nil {
-		return
-	}
+	return api.DiscoveryRulesDeletesByIDsContext(context.Background(), ids)
+}
 
-	if len(drulsids) != len(ids) {
-		err = &ExpectedMore{len(ids), len(drulsids)}
-	}
-	return
+// DiscoveryRulesDeletesByIDsContext is like DiscoveryRulesDeletesByIDs but uses ctx for the underlying requests.
+func (api *API) DiscoveryRulesDeletesByIDsContext(ctx context.Context, ids []string) (err error) {
+	return NewRepository[LLDRule](api).DeleteByIDs(ctx, ids)
 }
 
 // DiscoveryRulesDeletesIDs  Wrapper for discorveryrule.delete
 // Delete the item and return the id of the deleted item
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/discoveryrule/delete
 func (api *API) DiscoveryRulesDeletesIDs(ids []string) (drulsids []interface{}, err error) {
-	res, err := api.CallWithError("discoveryrule.delete", ids)
-	if err != nil {
-		return
-	}
+	return api.DiscoveryRulesDeletesIDsContext(context.Background(), ids)
+}
 
-	result := res.Result.(map[string]interface{})
-	drulsids = result["ruleids"].([]interface{})
+// DiscoveryRulesDeletesIDsContext is like DiscoveryRulesDeletesIDs but uses ctx for the underlying requests.
+func (api *API) DiscoveryRulesDeletesIDsContext(ctx context.Context, ids []string) (drulsids []interface{}, err error) {
+	deleted, err := NewRepository[LLDRule](api).deleteIDs(ctx, ids)
+	for _, id := range deleted {
+		drulsids = append(drulsids, id)
+	}
 	return
 }
@@ -0,0 +1,148 @@
+package zabbix
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// redactedValue replaces secret values in logged bodies.
+const redactedValue = "[REDACTED]"
+
+// secretFields are the keys redacted at any depth of logged request and response bodies.
+var secretFields = map[string]bool{
+	"auth":                  true, // request auth token
+	"password":              true, // user.login, items, LLD rules, action commands
+	"passwd":                true, // users and media types
+	"current_passwd":        true,
+	"token":                 true,
+	"sessionid":             true,
+	"snmp_community":        true,
+	"snmpv3_authpassphrase": true,
+	"snmpv3_privpassphrase": true,
+	"privatekey":            true,
+	"client_secret":         true,
+	"access_token":          true,
+	"refresh_token":         true,
+}
+
+// RedactFields Registers extra fields to redact from logged request and response bodies,
+// on top of known secrets like passwords, auth tokens and SNMPv3 passphrases.
+// A path is a dot separated list of keys from the root of the JSON-RPC object,
+// like "params.description", arrays being traversed transparently.
+// A path without dots redacts the key at any depth.
+func (api *API) RedactFields(paths ...string) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	redactPaths := api.redactPaths[:len(api.redactPaths):len(api.redactPaths)]
+	for _, path := range paths {
+		redactPaths = append(redactPaths, strings.Split(path, "."))
+	}
+	api.redactPaths = redactPaths
+}
+
+// printBody logs the formatted prefix and the JSON body b with secrets redacted, see redact.
+// Bodies are only redacted when api.Logger is set, as decoding and encoding
+// them again costs as much as the call itself for large results.
+func (api *API) printBody(b []byte, secretResult bool, format string, v ...interface{}) {
+	if api.Logger == nil {
+		return
+	}
+	api.Logger.Printf(format+": %s", append(v, api.redact(b, secretResult))...)
+}
+
+// redact returns a copy of the JSON body b with secrets replaced.
+// If secretResult is set, a string result is redacted too, as the session id returned by user.login.
+// Bodies which are not JSON are returned as is.
+func (api *API) redact(b []byte, secretResult bool) []byte {
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if d.Decode(&v) != nil {
+		return b
+	}
+	api.mu.RLock()
+	redactPaths := api.redactPaths
+	api.mu.RUnlock()
+	v = redactValue(v, nil, redactPaths, secretResult)
+	redacted, err := json.Marshal(v)
+	if err != nil {
+		return b
+	}
+	return redacted
+}
+
+func redactValue(v interface{}, path []string, redactPaths [][]string, secretResult bool) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			keyPath := append(path[:len(path):len(path)], key)
+			_, isString := value.(string)
+			if value != nil && (secretFields[strings.ToLower(key)] || isRedactedPath(keyPath, redactPaths) ||
+				(secretResult && isString && len(keyPath) == 1 && key == "result")) {
+				v[key] = redactedValue
+			} else {
+				v[key] = redactValue(value, keyPath, redactPaths, secretResult)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactValue(value, path, redactPaths, secretResult)
+		}
+	}
+	return v
+}
+
+// isRedactedPath reports whether path matches one of redactPaths, registered with RedactFields.
+func isRedactedPath(path []string, redactPaths [][]string) bool {
+	for _, redacted := range redactPaths {
+		if len(redacted) == 1 && redacted[0] == path[len(path)-1] {
+			return true
+		}
+		if len(redacted) == len(path) {
+			match := true
+			for i := range path {
+				if redacted[i] != path[i] {
+					match = false
+					break
+				}
+			}
+			if match {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// logExchange logs one attempt of ex to api.StructuredLogger, see observe.
+func (api *API) logExchange(ctx context.Context, ex *exchange, attempt, status, size int, duration time.Duration, err error) {
+	if api.StructuredLogger == nil {
+		return
+	}
+	level := slog.LevelDebug
+	if err != nil || status >= 400 {
+		level = slog.LevelWarn
+	}
+
+	attrs := []slog.Attr{slog.String("method", strings.Join(ex.methods, ","))}
+	if len(ex.ids) == 1 {
+		attrs = append(attrs, slog.Int("id", int(ex.ids[0])))
+	} else {
+		attrs = append(attrs, slog.Any("ids", ex.ids))
+	}
+	attrs = append(attrs,
+		slog.Int("attempt", attempt),
+		slog.Duration("duration", duration),
+		slog.Int("status", status),
+		slog.Int("request_size", len(ex.body)),
+		slog.Int("response_size", size),
+	)
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	api.StructuredLogger.LogAttrs(ctx, level, "zabbix api request", attrs...)
+}
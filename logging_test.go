@@ -0,0 +1,129 @@
+package zabbix_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	zapi "github.com/claranet/go-zabbix-api"
+)
+
+func TestLoggerRedaction(t *testing.T) {
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		if testRPCMethod(r) == "user.login" {
+			w.Write([]byte(`{"jsonrpc":"2.0","result":"0424bd59b807674191e7d77572075f33","id":1}`))
+			return
+		}
+		w.Write([]byte(`{"jsonrpc":"2.0","result":{"itemids":["1"]},"id":2}`))
+	})
+	var buf bytes.Buffer
+	api.Logger = log.New(&buf, "", 0)
+	api.RedactFields("params.description")
+
+	if _, err := api.Login("Admin", "zabbix"); err != nil {
+		t.Fatal(err)
+	}
+	items := zapi.ItemPrototypes{{
+		Key:                  "ssh.run[test]",
+		Description:          "internal notes",
+		Type:                 zapi.SNMPv3Agent,
+		Delay:                "1m",
+		Snmpv3Authpassphrase: "snmp-secret",
+	}}
+	if err := api.ItemPrototypesCreate(items); err != nil {
+		t.Fatal(err)
+	}
+
+	logs := buf.String()
+	for _, secret := range []string{"zabbix", "0424bd59b807674191e7d77572075f33", "snmp-secret", "internal notes"} {
+		if strings.Contains(logs, secret) {
+			t.Errorf("Secret %q leaked in logs:\n%s", secret, logs)
+		}
+	}
+	if !strings.Contains(logs, "ssh.run[test]") {
+		t.Errorf("Expected item key in logs:\n%s", logs)
+	}
+}
+
+// testLargeResultAPI returns an API whose host.get result has n hosts.
+func testLargeResultAPI(t testing.TB, n int) *zapi.API {
+	var body bytes.Buffer
+	body.WriteString(`{"jsonrpc":"2.0","result":[`)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			body.WriteByte(',')
+		}
+		fmt.Fprintf(&body, `{"hostid":"%d","host":"host-%d","name":"Host %d","status":"0"}`, i, i, i)
+	}
+	body.WriteString(`],"id":1}`)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body.Bytes())
+	}))
+	t.Cleanup(srv.Close)
+	api, err := zapi.NewAPIWithOptions(srv.URL, zapi.WithServerVersion("6.0.0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return api
+}
+
+func TestLoggerDisabledRedaction(t *testing.T) {
+	api := testLargeResultAPI(t, 1000)
+	call := func() {
+		var res []struct{}
+		if err := api.CallWithErrorParse("host.get", zapi.Params{}, &res); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	withoutLogger := testing.AllocsPerRun(5, call)
+	api.Logger = log.New(io.Discard, "", 0)
+	withLogger := testing.AllocsPerRun(5, call)
+	// Redacting decodes every field of the 1000 hosts
+	if withoutLogger*4 > withLogger {
+		t.Errorf("Expected the response to be redacted only with a logger, got %.0f allocations without and %.0f with", withoutLogger, withLogger)
+	}
+}
+
+func BenchmarkCallWithoutLogger(b *testing.B) {
+	api := testLargeResultAPI(b, 1000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var res []struct{}
+		if err := api.CallWithErrorParse("host.get", zapi.Params{}, &res); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestStructuredLogger(t *testing.T) {
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jsonrpc":"2.0","result":[],"id":1}`))
+	})
+	var buf bytes.Buffer
+	api.StructuredLogger = slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	if _, err := api.HostsGet(zapi.Params{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var record struct {
+		Method       string `json:"method"`
+		ID           int    `json:"id"`
+		Status       int    `json:"status"`
+		ResponseSize int    `json:"response_size"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Bad log record %q: %s", buf.String(), err)
+	}
+	if record.Method != "host.get" || record.ID == 0 || record.Status != 200 || record.ResponseSize == 0 {
+		t.Errorf("Bad log record: %s", buf.String())
+	}
+}
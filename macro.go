@@ -1,9 +1,11 @@
 package zabbix
 
+import "context"
+
 // Macro represent Zabbix User MAcro object
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/usermacro/object
 type Macro struct {
-	MacroID   string `json:"hostmacroids,omitempty"`
+	MacroID   string `json:"hostmacroid,omitempty"`
 	HostID    string `json:"hostid,omitempty"`
 	MacroName string `json:"macro"`
 	Value     string `json:"value"`
@@ -15,79 +17,67 @@ type Macros []Macro
 // MacrosGet Wrapper for usermacro.get
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/usermacro/get
 func (api *API) MacrosGet(params Params) (res Macros, err error) {
-	if _, present := params["output"]; !present {
-		params["output"] = "extend"
-	}
-	err = api.CallWithErrorParse("usermacro.get", params, &res)
-	return
+	return api.MacrosGetContext(context.Background(), params)
+}
+
+// MacrosGetContext is like MacrosGet but uses ctx for the underlying requests.
+func (api *API) MacrosGetContext(ctx context.Context, params Params) (res Macros, err error) {
+	return NewRepository[Macro](api).Get(ctx, params)
 }
 
 // MacroGetByID Get macro by macro ID if there is exactly 1 matching macro
 func (api *API) MacroGetByID(id string) (res *Macro, err error) {
-	triggers, err := api.MacrosGet(Params{"hostmacroids": id})
-	if err != nil {
-		return
-	}
+	return api.MacroGetByIDContext(context.Background(), id)
+}
 
-	if len(triggers) == 1 {
-		res = &triggers[0]
-	} else {
-		e := ExpectedOneResult(len(triggers))
-		err = &e
-	}
-	return
+// MacroGetByIDContext is like MacroGetByID but uses ctx for the underlying requests.
+func (api *API) MacroGetByIDContext(ctx context.Context, id string) (res *Macro, err error) {
+	return NewRepository[Macro](api).GetByID(ctx, id)
 }
 
 // MacrosCreate Wrapper for usermacro.create
+// Sets MacroID of the created macros; before the Repository it wrongly set HostID.
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/usermacro/create
 func (api *API) MacrosCreate(macros Macros) error {
-	response, err := api.CallWithError("usermacro.create", macros)
-	if err != nil {
-		return err
-	}
+	return api.MacrosCreateContext(context.Background(), macros)
+}
 
-	result := response.Result.(map[string]interface{})
-	macroids := result["hostmacroids"].([]interface{})
-	for i, id := range macroids {
-		macros[i].HostID = id.(string)
-	}
-	return nil
+// MacrosCreateContext is like MacrosCreate but uses ctx for the underlying requests.
+func (api *API) MacrosCreateContext(ctx context.Context, macros Macros) error {
+	return NewRepository[Macro](api).Create(ctx, macros)
 }
 
 // MacrosUpdate Wrapper for usermacro.update
+// Before the Repository it wrongly called usermacro.create.
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/usermacro/update
 func (api *API) MacrosUpdate(macros Macros) (err error) {
-	_, err = api.CallWithError("usermacro.create", macros)
-	return
+	return api.MacrosUpdateContext(context.Background(), macros)
+}
+
+// MacrosUpdateContext is like MacrosUpdate but uses ctx for the underlying requests.
+func (api *API) MacrosUpdateContext(ctx context.Context, macros Macros) (err error) {
+	return NewRepository[Macro](api).Update(ctx, macros)
 }
 
 // MacrosDeleteByIDs Wrapper for usermacro.delete
 // Cleans MacroId in all macro elements if call succeed.
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/usermacro/delete
 func (api *API) MacrosDeleteByIDs(ids []string) (err error) {
-	response, err := api.CallWithError("usermacro.delete", ids)
+	return api.MacrosDeleteByIDsContext(context.Background(), ids)
+}
 
-	result := response.Result.(map[string]interface{})
-	hostmacroids := result["hostmacroids"].([]interface{})
-	if len(ids) != len(hostmacroids) {
-		err = &ExpectedMore{len(ids), len(hostmacroids)}
-	}
-	return
+// MacrosDeleteByIDsContext is like MacrosDeleteByIDs but uses ctx for the underlying requests.
+func (api *API) MacrosDeleteByIDsContext(ctx context.Context, ids []string) (err error) {
+	return NewRepository[Macro](api).DeleteByIDs(ctx, ids)
 }
 
 // MacrosDelete Wrapper for usermacro.delete
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/usermacro/delete
 func (api *API) MacrosDelete(macros Macros) (err error) {
-	ids := make([]string, len(macros))
-	for i, macro := range macros {
-		ids[i] = macro.MacroID
-	}
+	return api.MacrosDeleteContext(context.Background(), macros)
+}
 
-	err = api.MacrosDeleteByIDs(ids)
-	if err == nil {
-		for i := range macros {
-			macros[i].MacroID = ""
-		}
-	}
-	return
+// MacrosDeleteContext is like MacrosDelete but uses ctx for the underlying requests.
+func (api *API) MacrosDeleteContext(ctx context.Context, macros Macros) (err error) {
+	return NewRepository[Macro](api).Delete(ctx, macros)
 }
@@ -0,0 +1,85 @@
+package zabbix_test
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	zapi "github.com/claranet/go-zabbix-api"
+)
+
+func TestMacrosCreateUpdate(t *testing.T) {
+	var methods []string
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, testRPCMethod(r))
+		w.Write([]byte(`{"jsonrpc":"2.0","result":{"hostmacroids":["42"]},"id":1}`))
+	})
+
+	macros := zapi.Macros{{HostID: "10084", MacroName: "{$SNMP_COMMUNITY}", Value: "public"}}
+	if err := api.MacrosCreate(macros); err != nil {
+		t.Fatal(err)
+	}
+	if macros[0].MacroID != "42" || macros[0].HostID != "10084" {
+		t.Errorf("Expected MacroID 42 and HostID 10084, got %#v", macros[0])
+	}
+
+	macros[0].Value = "private"
+	if err := api.MacrosUpdate(macros); err != nil {
+		t.Fatal(err)
+	}
+	if len(methods) != 2 || methods[0] != "usermacro.create" || methods[1] != "usermacro.update" {
+		t.Errorf("Unexpected methods %v", methods)
+	}
+}
+
+func TestMacros(t *testing.T) {
+	api := testGetAPI(t)
+
+	group := testCreateHostGroup(t)
+	defer testDeleteHostGroup(group, t)
+	host := testCreateHost(group, t)
+	defer testDeleteHost(host, t)
+
+	macros := zapi.Macros{
+		{HostID: host.HostID, MacroName: "{$ONE}", Value: "1"},
+		{HostID: host.HostID, MacroName: "{$TWO}", Value: "2"},
+		{HostID: host.HostID, MacroName: "{$THREE}", Value: "3"},
+	}
+	if err := api.MacrosCreate(macros); err != nil {
+		t.Fatal(err)
+	}
+
+	pager := zapi.NewRepository[zapi.Macro](api).Pager(zapi.Params{"hostids": host.HostID}, 2)
+	var pages [][]string
+	for pager.Next(context.Background()) {
+		var ids []string
+		for _, macro := range pager.Page() {
+			ids = append(ids, macro.MacroID)
+		}
+		pages = append(pages, ids)
+	}
+	if err := pager.Err(); err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]string{{macros[0].MacroID, macros[1].MacroID}, {macros[2].MacroID}}
+	if !reflect.DeepEqual(pages, expected) {
+		t.Errorf("pages = %v, want %v", pages, expected)
+	}
+
+	macros[1].Value = "deux"
+	if err := api.MacrosUpdate(macros[1:2]); err != nil {
+		t.Fatal(err)
+	}
+	macro, err := api.MacroGetByID(macros[1].MacroID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if macro.MacroID != macros[1].MacroID || macro.Value != "deux" {
+		t.Errorf("Unexpected updated macro %#v", macro)
+	}
+
+	if err = api.MacrosDelete(macros); err != nil {
+		t.Fatal(err)
+	}
+}
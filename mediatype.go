@@ -1,5 +1,7 @@
 package zabbix
 
+import "context"
+
 type (
 	// Media type.
 	// "type" in https://www.zabbix.com/documentation/current/manual/api/reference/mediatype/object
@@ -19,6 +21,7 @@ const (
 // Version compatibility notes:
 // - Zabbix 7.0+ uses MessageFormat field
 // - Zabbix 6.4 and earlier use ContentType field
+// Either field is sent as the one the server knows, see FeatureMessageFormat.
 type MediaType struct {
 	MediaTypeID        string        `json:"mediatypeid,omitempty"`
 	Type               MediaTypeType `json:"type,string,omitempty"`
@@ -61,9 +64,10 @@ type MediaTypes []MediaType
 // MediaTypeGet Wrapper for mediatype.get
 // https://www.zabbix.com/documentation/current/manual/api/reference/mediatype/get
 func (api *API) MediaTypesGet(params Params) (res MediaTypes, err error) {
-	if _, present := params["output"]; !present {
-		params["output"] = "extend"
-	}
-	err = api.CallWithErrorParse("mediatype.get", params, &res)
-	return
+	return api.MediaTypesGetContext(context.Background(), params)
+}
+
+// MediaTypesGetContext is like MediaTypesGet but uses ctx for the underlying requests.
+func (api *API) MediaTypesGetContext(ctx context.Context, params Params) (res MediaTypes, err error) {
+	return NewRepository[MediaType](api).Get(ctx, params)
 }
@@ -0,0 +1,186 @@
+package zabbix
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"expvar"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Error classes of RequestMetrics.
+const (
+	ClassNetwork          = "network"           // the request got no HTTP response
+	ClassCanceled         = "canceled"          // the context was canceled
+	ClassTimeout          = "timeout"           // the context deadline was exceeded
+	ClassHTTP             = "http"              // the response has an HTTP error status
+	ClassProtocol         = "protocol"          // the response is not a JSON-RPC response, like an HTML page
+	ClassNotFound         = "not_found"         // see ErrNotFound
+	ClassAlreadyExists    = "already_exists"    // see ErrAlreadyExists
+	ClassPermissionDenied = "permission_denied" // see ErrPermissionDenied
+	ClassSessionExpired   = "session_expired"   // see ErrSessionExpired
+	ClassInvalidParams    = "invalid_params"    // see ErrInvalidParams
+	ClassUnsupported      = "unsupported"       // see ErrVersionUnsupported
+	ClassAPI              = "api"               // any other API error
+)
+
+// RequestMetrics describes one HTTP request sent to the server.
+type RequestMetrics struct {
+	// Method is the JSON-RPC method, or "batch" for a batch of different methods.
+	Method string
+	// Calls is the number of JSON-RPC calls of the request, 1 unless it is a batch.
+	Calls int
+	// Attempt is 1 for the first attempt of a call and increases on every retry.
+	Attempt  int
+	Duration time.Duration
+	// BytesSent and BytesReceived are the sizes of the request and response bodies.
+	// BytesReceived is 0 for streamed responses, which are not read at once.
+	BytesSent     int
+	BytesReceived int
+	// Status is the HTTP status code, 0 when there was no response.
+	Status int
+	// ErrorClass is empty on success, or one of the Class* constants.
+	ErrorClass string
+}
+
+// Metrics receives one observation per HTTP request, including retries.
+// ObserveRequest is called concurrently and must not block.
+type Metrics interface {
+	ObserveRequest(ctx context.Context, m RequestMetrics)
+}
+
+// NopMetrics discards observations, it is the default API.Metrics.
+type NopMetrics struct{}
+
+func (NopMetrics) ObserveRequest(context.Context, RequestMetrics) {}
+
+// ExpvarMetrics publishes request counters by method with package expvar:
+//
+//	{"host.get": {"requests": 12, "errors": 1, "error_timeout": 1, "status_200": 11,
+//		"duration_seconds": 0.84, "bytes_sent": 1440, "bytes_received": 98304}}
+//
+// The average latency is duration_seconds divided by requests.
+type ExpvarMetrics struct {
+	mu      sync.Mutex // serializes the creation of method maps
+	methods *expvar.Map
+}
+
+// NewExpvarMetrics Publishes the counters as the expvar variable name.
+// Like expvar.Publish, it panics if name is already published.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	return &ExpvarMetrics{methods: expvar.NewMap(name)}
+}
+
+// Map Returns the published counters.
+func (e *ExpvarMetrics) Map() *expvar.Map {
+	return e.methods
+}
+
+func (e *ExpvarMetrics) ObserveRequest(_ context.Context, m RequestMetrics) {
+	counters, ok := e.methods.Get(m.Method).(*expvar.Map)
+	if !ok {
+		e.mu.Lock()
+		if counters, ok = e.methods.Get(m.Method).(*expvar.Map); !ok {
+			counters = new(expvar.Map)
+			e.methods.Set(m.Method, counters)
+		}
+		e.mu.Unlock()
+	}
+
+	counters.Add("requests", 1)
+	counters.AddFloat("duration_seconds", m.Duration.Seconds())
+	counters.Add("bytes_sent", int64(m.BytesSent))
+	counters.Add("bytes_received", int64(m.BytesReceived))
+	if m.Status != 0 {
+		counters.Add("status_"+strconv.Itoa(m.Status), 1)
+	}
+	if m.ErrorClass != "" {
+		counters.Add("errors", 1)
+		counters.Add("error_"+m.ErrorClass, 1)
+	}
+}
+
+// observe reports one attempt of ex to the structured logger and to api.Metrics.
+// body is the response body, nil when it was not read.
+func (api *API) observe(ctx context.Context, ex *exchange, attempt, status int, body []byte, duration time.Duration, err error) {
+	api.logExchange(ctx, ex, attempt, status, len(body), duration, err)
+	if api.Metrics == nil {
+		return
+	}
+
+	method := ex.methods[0]
+	for _, m := range ex.methods[1:] {
+		if m != method {
+			method = "batch"
+			break
+		}
+	}
+	api.Metrics.ObserveRequest(ctx, RequestMetrics{
+		Method:        method,
+		Calls:         len(ex.methods),
+		Attempt:       attempt,
+		Duration:      duration,
+		BytesSent:     len(ex.body),
+		BytesReceived: len(body),
+		Status:        status,
+		ErrorClass:    errorClass(status, body, err),
+	})
+}
+
+// errorClass classifies the failure of a request, empty on success.
+// The error of a response is read from body, for the first call of batches.
+func errorClass(status int, body []byte, err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return ClassCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ClassTimeout
+	case err != nil:
+		var e *Error
+		if errors.As(err, &e) {
+			return apiErrorClass(e)
+		}
+		return ClassNetwork
+	case status >= 400:
+		return ClassHTTP
+	}
+
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		body = bytes.TrimSpace(body[1:])
+	}
+	if len(body) == 0 {
+		return ""
+	}
+	// openResponse stops at the result, so successful responses are not decoded
+	if _, err := openResponse("", bytes.NewReader(body)); err != nil {
+		var e *Error
+		if errors.As(err, &e) {
+			return apiErrorClass(e)
+		}
+		return ClassProtocol
+	}
+	return ""
+}
+
+// apiErrorClass returns the class of the API error e.
+func apiErrorClass(e *Error) string {
+	for _, c := range []struct {
+		err   error
+		class string
+	}{
+		{ErrVersionUnsupported, ClassUnsupported},
+		{ErrSessionExpired, ClassSessionExpired},
+		{ErrAlreadyExists, ClassAlreadyExists},
+		{ErrNotFound, ClassNotFound},
+		{ErrPermissionDenied, ClassPermissionDenied},
+		{ErrInvalidParams, ClassInvalidParams},
+	} {
+		if errors.Is(e, c.err) {
+			return c.class
+		}
+	}
+	return ClassAPI
+}
@@ -0,0 +1,118 @@
+package zabbix_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	zapi "github.com/claranet/go-zabbix-api"
+)
+
+type testMetrics struct {
+	mu           sync.Mutex
+	observations []zapi.RequestMetrics
+}
+
+func (m *testMetrics) ObserveRequest(_ context.Context, r zapi.RequestMetrics) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.observations = append(m.observations, r)
+}
+
+func TestMetrics(t *testing.T) {
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		switch testRPCMethod(r) {
+		case "host.get":
+			fmt.Fprint(w, `{"jsonrpc":"2.0","result":[],"id":1}`)
+		case "host.create":
+			fmt.Fprint(w, `{"jsonrpc":"2.0","error":{"code":-32602,"message":"Invalid params.","data":"Host with the same name \"a\" already exists."},"id":1}`)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+	metrics := &testMetrics{}
+	api.Metrics = metrics
+
+	api.HostsGet(zapi.Params{})
+	api.HostsCreate(zapi.Hosts{{Host: "a"}})
+	api.CallWithError("host.delete", []string{"1"})
+
+	if len(metrics.observations) != 3 {
+		t.Fatalf("got %d observations, want 3", len(metrics.observations))
+	}
+	for i, want := range []struct {
+		method, class string
+		status        int
+	}{
+		{"host.get", "", 200},
+		{"host.create", zapi.ClassAlreadyExists, 200},
+		{"host.delete", zapi.ClassHTTP, 500},
+	} {
+		m := metrics.observations[i]
+		if m.Method != want.method || m.ErrorClass != want.class || m.Status != want.status {
+			t.Errorf("observation %d = %+v, want %+v", i, m, want)
+		}
+		if m.Calls != 1 || m.Attempt != 1 || m.BytesSent == 0 || m.Duration <= 0 {
+			t.Errorf("observation %d = %+v", i, m)
+		}
+	}
+	if m := metrics.observations[0]; m.BytesReceived != len(`{"jsonrpc":"2.0","result":[],"id":1}`) {
+		t.Errorf("BytesReceived = %d", m.BytesReceived)
+	}
+}
+
+func TestMetricsProtocolError(t *testing.T) {
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		if testRPCMethod(r) == "host.delete" {
+			fmt.Fprint(w, `{"jsonrpc":"2.0","result":{"hostids":["1"]},"id":1}`)
+			return
+		}
+		fmt.Fprint(w, `<html><body><h1>502 Bad Gateway</h1></body></html>`)
+	})
+	metrics := &testMetrics{}
+	api.Metrics = metrics
+
+	if _, err := api.HostsGet(zapi.Params{}); err == nil {
+		t.Error("Expected an error for an HTML response")
+	}
+	if err := api.HostsDeleteByIds([]string{"1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the HTML response is retried
+	for _, m := range metrics.observations {
+		switch {
+		case m.Method == "host.get" && (m.Status != 200 || m.ErrorClass != zapi.ClassProtocol):
+			t.Errorf("observation of the HTML response = %+v", m)
+		case m.Method == "host.delete" && m.ErrorClass != "":
+			t.Errorf("observation of the object result = %+v", m)
+		}
+	}
+	if m := metrics.observations[len(metrics.observations)-1]; m.Method != "host.delete" {
+		t.Errorf("last observation = %+v", m)
+	}
+}
+
+func TestExpvarMetrics(t *testing.T) {
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"jsonrpc":"2.0","result":[],"id":1}`)
+	})
+	// expvar names are global, -count runs the test several times
+	metrics := zapi.NewExpvarMetrics(fmt.Sprintf("zabbix_test_requests_%d", time.Now().UnixNano()))
+	api.Metrics = metrics
+
+	api.HostsGet(zapi.Params{})
+	api.HostsGet(zapi.Params{})
+
+	var counters map[string]map[string]float64
+	if err := json.Unmarshal([]byte(metrics.Map().String()), &counters); err != nil {
+		t.Fatal(err)
+	}
+	if c := counters["host.get"]; c["requests"] != 2 || c["status_200"] != 2 || c["errors"] != 0 || c["bytes_sent"] == 0 {
+		t.Errorf("host.get counters = %v", c)
+	}
+}
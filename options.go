@@ -0,0 +1,182 @@
+package zabbix
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+
+	"github.com/hashicorp/go-version"
+)
+
+// Option configures an API created by NewAPIWithOptions.
+type Option func(api *API) error
+
+// NewAPIWithOptions Creates new API access object configured with opts.
+// Unless WithServerVersion or WithLazyVersionDetection is given,
+// the server version is requested like NewAPI does.
+//
+//	api, err := zabbix.NewAPIWithOptions("http://host/api_jsonrpc.php",
+//		zabbix.WithServerVersion("7.0.0"),
+//		zabbix.WithToken(token),
+//	)
+func NewAPIWithOptions(url string, opts ...Option) (api *API, err error) {
+	return NewAPIWithOptionsContext(context.Background(), url, opts...)
+}
+
+// NewAPIWithOptionsContext is like NewAPIWithOptions but uses ctx for the server version request.
+func NewAPIWithOptionsContext(ctx context.Context, url string, opts ...Option) (api *API, err error) {
+	api = &API{url: url, c: &http.Client{}, UserAgent: "github.com/claranet/zabbix", Retry: DefaultRetryPolicy(), ChunkSize: DefaultChunkSize, Metrics: NopMetrics{}}
+	for _, opt := range opts {
+		if err = opt(api); err != nil {
+			return nil, err
+		}
+	}
+
+	if api.ServerVersion == nil && !api.lazyVersion {
+		// Like NewAPI always did, api is returned along with a failed version request error
+		err = api.detectServerVersion(ctx)
+	}
+	return
+}
+
+// WithHTTPClient Uses c for HTTP requests, see SetClient.
+func WithHTTPClient(c *http.Client) Option {
+	return func(api *API) error {
+		api.SetClient(c)
+		return nil
+	}
+}
+
+// WithUserAgent Sets the User-Agent header of HTTP requests.
+func WithUserAgent(userAgent string) Option {
+	return func(api *API) error {
+		api.UserAgent = userAgent
+		return nil
+	}
+}
+
+// WithServerVersion Sets the server version instead of requesting it, so no request is made on creation.
+func WithServerVersion(v string) Option {
+	return func(api *API) (err error) {
+		api.ServerVersion, err = parseServerVersion(v)
+		return
+	}
+}
+
+// WithLazyVersionDetection Delays the server version request until it is needed,
+// by Login() or by the first authenticated call.
+func WithLazyVersionDetection() Option {
+	return func(api *API) error {
+		api.lazyVersion = true
+		return nil
+	}
+}
+
+// WithLogger Sets the request/response logger, see API.Logger.
+func WithLogger(logger *log.Logger) Option {
+	return func(api *API) error {
+		api.Logger = logger
+		return nil
+	}
+}
+
+// WithStructuredLogger Sets the structured logger, see API.StructuredLogger.
+func WithStructuredLogger(logger *slog.Logger) Option {
+	return func(api *API) error {
+		api.StructuredLogger = logger
+		return nil
+	}
+}
+
+// WithMetrics Sets the request metrics, see API.Metrics.
+func WithMetrics(m Metrics) Option {
+	return func(api *API) error {
+		api.Metrics = m
+		return nil
+	}
+}
+
+// WithTracer Sets the tracer of JSON-RPC calls, see API.Tracer.
+func WithTracer(t Tracer) Option {
+	return func(api *API) error {
+		api.Tracer = t
+		return nil
+	}
+}
+
+// WithToken Authenticates requests with an API token, see SetToken.
+func WithToken(token string) Option {
+	return func(api *API) error {
+		api.SetToken(token)
+		return nil
+	}
+}
+
+// WithRetryPolicy Sets the retry policy, see API.Retry.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(api *API) error {
+		api.Retry = policy
+		return nil
+	}
+}
+
+// WithChunkSize Sets API.ChunkSize, 0 disables chunking.
+func WithChunkSize(n int) Option {
+	return func(api *API) error {
+		if n < 0 {
+			return fmt.Errorf("zabbix: invalid chunk size %d", n)
+		}
+		api.ChunkSize = n
+		return nil
+	}
+}
+
+// WithLimits Sets the limits of read-only and other requests, see SetLimits.
+func WithLimits(read, write Limits) Option {
+	return func(api *API) error {
+		api.SetLimits(read, write)
+		return nil
+	}
+}
+
+// parseServerVersion parses the version returned by "apiinfo.version".
+func parseServerVersion(v string) (*version.Version, error) {
+	parsed, err := version.NewVersion(v)
+	if err != nil {
+		return nil, fmt.Errorf("zabbix: cannot parse server version %q: %w", v, err)
+	}
+	return parsed, nil
+}
+
+// detectServerVersion requests the server version and stores it in api.ServerVersion.
+func (api *API) detectServerVersion(ctx context.Context) error {
+	rawVersion, err := api.VersionContext(ctx)
+	if err != nil {
+		return err
+	}
+	serverVersion, err := parseServerVersion(rawVersion)
+	if err != nil {
+		return err
+	}
+	api.setServerVersion(serverVersion)
+	return nil
+}
+
+// detectingVersionKey marks contexts of the lazy server version request.
+type detectingVersionKey struct{}
+
+// ensureServerVersion detects the server version if it is still unknown and detection is lazy.
+func (api *API) ensureServerVersion(ctx context.Context) error {
+	if !api.lazyVersion || ctx.Value(detectingVersionKey{}) != nil || api.serverVersion() != nil {
+		return nil
+	}
+
+	api.versionMu.Lock()
+	defer api.versionMu.Unlock()
+	if api.serverVersion() != nil {
+		return nil
+	}
+	return api.detectServerVersion(context.WithValue(ctx, detectingVersionKey{}, true))
+}
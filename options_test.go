@@ -0,0 +1,78 @@
+package zabbix_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	zapi "github.com/claranet/go-zabbix-api"
+)
+
+func TestNewAPIWithOptionsOffline(t *testing.T) {
+	api, err := zapi.NewAPIWithOptions("http://127.0.0.1:1/api_jsonrpc.php",
+		zapi.WithServerVersion("7.0.3"),
+		zapi.WithUserAgent("test-agent"),
+		zapi.WithToken("secret"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if api.ServerVersion.String() != "7.0.3" || api.UserAgent != "test-agent" || api.Auth != "secret" {
+		t.Errorf("Options not applied: %#v", api)
+	}
+
+	_, err = zapi.NewAPIWithOptions("http://127.0.0.1:1/api_jsonrpc.php", zapi.WithServerVersion("not a version"))
+	if err == nil {
+		t.Error("Expected error for an invalid server version")
+	}
+}
+
+func TestNewAPIWithOptionsLazyVersion(t *testing.T) {
+	var versionRequests int
+	var loginParams map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch testRPCMethod(r) {
+		case "APIInfo.version":
+			versionRequests++
+			w.Write([]byte(`{"jsonrpc":"2.0","result":"5.0.30","id":1}`))
+		case "user.login":
+			var req struct {
+				Params map[string]string `json:"params"`
+			}
+			testDecodeRequest(r, &req)
+			loginParams = req.Params
+			w.Write([]byte(`{"jsonrpc":"2.0","result":"session","id":2}`))
+		}
+	}))
+	defer srv.Close()
+
+	api, err := zapi.NewAPIWithOptions(srv.URL, zapi.WithLazyVersionDetection())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if versionRequests != 0 || api.ServerVersion != nil {
+		t.Fatalf("Expected no version request on creation, got %d", versionRequests)
+	}
+
+	if _, err = api.Login("Admin", "zabbix"); err != nil {
+		t.Fatal(err)
+	}
+	if versionRequests != 1 || api.ServerVersion.String() != "5.0.30" {
+		t.Errorf("Expected version to be detected by Login, got %d requests and %v", versionRequests, api.ServerVersion)
+	}
+	if loginParams["user"] != "Admin" {
+		t.Errorf("Expected Zabbix 5.0 login parameters, got %v", loginParams)
+	}
+}
+
+func TestNewAPIBadVersion(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"jsonrpc":"2.0","result":"garbage","id":1}`)
+	}))
+	defer srv.Close()
+
+	if _, err := zapi.NewAPI(srv.URL); err == nil {
+		t.Error("Expected error for an unparsable server version")
+	}
+}
@@ -0,0 +1,124 @@
+package zabbix
+
+import (
+	"context"
+	"reflect"
+	"strings"
+)
+
+// Pager gets the objects matching params one page at a time.
+// Zabbix get methods have no offset parameter, so the pager first streams the
+// sorted IDs of all matching objects, which is cheap, then gets each page by its
+// range of IDs, sorted by ID with sortfield and limited to the page size.
+//
+// Zabbix get methods have no "greater than" filter either, so the IDs cannot be
+// paged by range on the server: the pager keeps the IDs left to get in memory,
+// a few bytes per object. The IDs are scanned by the first call to Next, and each
+// page is got later by its IDs, so the pages are a snapshot of the IDs but not of
+// the objects. Objects created after the scan are not returned, and objects
+// deleted or no longer matching params before their page is got are skipped,
+// leaving a shorter page. Use Iter for a single consistent response.
+//
+//	pager := zabbix.NewRepository[zabbix.Item](api).Pager(zabbix.Params{"hostids": hostIDs}, 1000)
+//	for pager.Next(ctx) {
+//		for _, item := range pager.Page() {
+//			...
+//		}
+//	}
+//	if err := pager.Err(); err != nil {
+//		...
+//	}
+type Pager[T any] struct {
+	repo   *Repository[T]
+	params Params
+	size   int
+	ids    []string
+	loaded bool
+	page   []T
+	err    error
+}
+
+// Pager Returns a Pager of the objects matching params, size objects at a time.
+func (r *Repository[T]) Pager(params Params, size int) *Pager[T] {
+	if size <= 0 {
+		size = DefaultChunkSize
+	}
+	return &Pager[T]{repo: r, params: params, size: size}
+}
+
+// Next Gets the next page, returning false when there is none left or on error.
+func (p *Pager[T]) Next(ctx context.Context) bool {
+	p.page = nil
+	if p.err != nil {
+		return false
+	}
+	if !p.loaded {
+		p.ids, p.err = p.repo.sortedIDs(ctx, p.params)
+		p.loaded = true
+		if p.err != nil {
+			return false
+		}
+	}
+	if len(p.ids) == 0 {
+		return false
+	}
+
+	n := p.size
+	if n > len(p.ids) {
+		n = len(p.ids)
+	}
+	params := make(Params, len(p.params)+3)
+	for k, v := range p.params {
+		params[k] = v
+	}
+	idName := p.repo.idJSONName()
+	params[p.repo.info.idsParam] = p.ids[:n]
+	params["sortfield"] = idName
+	params["sortorder"] = SortAsc
+	params["limit"] = n
+	p.page, p.err = p.repo.Get(ctx, params)
+	p.ids = p.ids[n:]
+	return p.err == nil
+}
+
+// Page Returns the objects of the page got by the last call to Next.
+func (p *Pager[T]) Page() []T {
+	return p.page
+}
+
+// Err Returns the error which stopped Next, if any.
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// sortedIDs streams the IDs of the objects matching params, sorted by ID.
+func (r *Repository[T]) sortedIDs(ctx context.Context, params Params) (ids []string, err error) {
+	idName := r.idJSONName()
+	p := make(Params, len(params)+3)
+	for k, v := range params {
+		p[k] = v
+	}
+	p["output"] = []string{idName}
+	p["sortfield"] = idName
+	p["sortorder"] = SortAsc
+	delete(p, "limit")
+	for k := range p {
+		if strings.HasPrefix(k, "select") {
+			delete(p, k)
+		}
+	}
+
+	s := NewStream[map[string]string](ctx, r.api, r.method("get"), r.api.encodeGetParams(r.info.prefix, p))
+	defer s.Close()
+	for s.Next() {
+		ids = append(ids, s.Value()[idName])
+	}
+	return ids, s.Err()
+}
+
+// idJSONName returns the json name of the ID field of T, like "hostid".
+func (r *Repository[T]) idJSONName() string {
+	f, _ := reflect.TypeOf((*T)(nil)).Elem().FieldByName(r.info.idField)
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	return name
+}
@@ -0,0 +1,180 @@
+package zabbix
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+type (
+	// TagOperator is the operator of a tag filter.
+	// "operator" of "tags" in https://www.zabbix.com/documentation/current/manual/api/reference/problem/get
+	TagOperator int
+
+	// TagEvalType is how tag filters are combined.
+	// "evaltype" in https://www.zabbix.com/documentation/current/manual/api/reference/problem/get
+	TagEvalType int
+)
+
+const (
+	// TagContains matches values containing the filter value
+	TagContains TagOperator = 0
+	// TagEquals matches values equal to the filter value
+	TagEquals TagOperator = 1
+	// TagNotContains matches values not containing the filter value
+	TagNotContains TagOperator = 2
+	// TagNotEquals matches values not equal to the filter value
+	TagNotEquals TagOperator = 3
+	// TagExists matches objects having the tag
+	TagExists TagOperator = 4
+	// TagNotExists matches objects not having the tag
+	TagNotExists TagOperator = 5
+)
+
+const (
+	// TagAndOr matches all the filters of different tags, and any filter of the same tag
+	TagAndOr TagEvalType = 0
+	// TagOr matches any of the filters
+	TagOr TagEvalType = 2
+)
+
+// TagFilter is a filter on the tags of problems or events.
+type TagFilter struct {
+	Tag      string      `json:"tag"`
+	Value    string      `json:"value"`
+	Operator TagOperator `json:"operator"`
+}
+
+// ProblemEvent represent Zabbix problem object, an unresolved or recently resolved problem event.
+// It is not named Problem, which is the trigger value.
+// https://www.zabbix.com/documentation/current/manual/api/reference/problem/object
+type ProblemEvent struct {
+	EventID       string          `json:"eventid"`
+	Source        EventType       `json:"source"`
+	Object        EventObjectType `json:"object,string"`
+	ObjectID      string          `json:"objectid"`
+	Clock         time.Time       `json:"clock"` // with nanoseconds
+	REventID      string          `json:"r_eventid"`
+	RClock        time.Time       `json:"r_clock"`                 // zero while unresolved
+	CauseEventID  string          `json:"cause_eventid,omitempty"` // cause of symptom problems, from Zabbix 7.0
+	CorrelationID string          `json:"correlationid"`
+	UserID        string          `json:"userid"`
+	Name          string          `json:"name"`
+	Acknowledged  int             `json:"acknowledged,string"`
+	Severity      SeverityType    `json:"severity,string"`
+	Suppressed    int             `json:"suppressed,string"`
+	OpData        string          `json:"opdata"`
+	URLs          ProblemURLs     `json:"urls,omitempty"`
+
+	// Fields below used if specified selectAcknowledges, selectTags or selectSuppressionData parameters
+	Acknowledges    Acknowledges        `json:"acknowledges,omitempty"`
+	Tags            Tags                `json:"tags,omitempty"`
+	SuppressionData SuppressionDataList `json:"suppression_data,omitempty"`
+}
+
+// ProblemEvents is an array of ProblemEvent
+type ProblemEvents []ProblemEvent
+
+// UnmarshalJSON decodes the clock, ns, r_clock and r_ns fields of the problem.
+func (p *ProblemEvent) UnmarshalJSON(b []byte) error {
+	type problem ProblemEvent
+	raw := struct {
+		*problem
+		Clock  string `json:"clock"`
+		NS     string `json:"ns"`
+		RClock string `json:"r_clock"`
+		RNS    string `json:"r_ns"`
+	}{problem: (*problem)(p)}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	p.Clock = unixTime(raw.Clock, raw.NS)
+	p.RClock = unixTime(raw.RClock, raw.RNS)
+	return nil
+}
+
+// ProblemURL is a media type URL of a problem.
+type ProblemURL struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// ProblemURLs is an array of ProblemURL
+type ProblemURLs []ProblemURL
+
+// ProblemQuery builds the parameters of problem.get, with the options specific to problems.
+// Set them before the GetQuery options, whose methods return the embedded *GetQuery:
+//
+//	q := zabbix.NewProblemQuery().
+//		Severities(zabbix.High, zabbix.Critical).
+//		Acknowledged(false).
+//		Tags(zabbix.TagAndOr, zabbix.TagFilter{Tag: "service", Value: "web", Operator: zabbix.TagEquals})
+//	q.Select("selectTags").Limit(100)
+//	problems, err := api.ProblemsQuery(ctx, q)
+type ProblemQuery struct {
+	*GetQuery[ProblemEvent]
+}
+
+// NewProblemQuery Creates an empty query of problems.
+func NewProblemQuery() *ProblemQuery {
+	return &ProblemQuery{NewGetQuery[ProblemEvent]()}
+}
+
+// Recent Returns recently resolved problems too, not only unresolved ones.
+func (q *ProblemQuery) Recent() *ProblemQuery {
+	q.params["recent"] = true
+	return q
+}
+
+// Severities Returns only the problems with one of severities.
+func (q *ProblemQuery) Severities(severities ...SeverityType) *ProblemQuery {
+	q.params["severities"] = severities
+	return q
+}
+
+// Suppressed Returns only the suppressed problems, or only the unsuppressed ones.
+func (q *ProblemQuery) Suppressed(suppressed bool) *ProblemQuery {
+	q.params["suppressed"] = suppressed
+	return q
+}
+
+// Acknowledged Returns only the acknowledged problems, or only the unacknowledged ones.
+func (q *ProblemQuery) Acknowledged(acknowledged bool) *ProblemQuery {
+	q.params["acknowledged"] = acknowledged
+	return q
+}
+
+// TimeFrom Returns only the problems created at or after t.
+func (q *ProblemQuery) TimeFrom(t time.Time) *ProblemQuery {
+	q.params["time_from"] = t.Unix()
+	return q
+}
+
+// TimeTill Returns only the problems created at or before t.
+func (q *ProblemQuery) TimeTill(t time.Time) *ProblemQuery {
+	q.params["time_till"] = t.Unix()
+	return q
+}
+
+// Tags Returns only the problems whose tags match filters, combined as evalType tells.
+func (q *ProblemQuery) Tags(evalType TagEvalType, filters ...TagFilter) *ProblemQuery {
+	q.params["evaltype"] = evalType
+	q.params["tags"] = filters
+	return q
+}
+
+// ProblemsGet Wrapper for problem.get
+// https://www.zabbix.com/documentation/current/manual/api/reference/problem/get
+func (api *API) ProblemsGet(params Params) (res ProblemEvents, err error) {
+	return api.ProblemsGetContext(context.Background(), params)
+}
+
+// ProblemsGetContext is like ProblemsGet but uses ctx for the underlying requests.
+func (api *API) ProblemsGetContext(ctx context.Context, params Params) (res ProblemEvents, err error) {
+	return NewRepository[ProblemEvent](api).Get(ctx, params)
+}
+
+// ProblemsQuery Gets the problems matching q.
+func (api *API) ProblemsQuery(ctx context.Context, q *ProblemQuery) (res ProblemEvents, err error) {
+	return NewRepository[ProblemEvent](api).Query(ctx, q.GetQuery)
+}
@@ -0,0 +1,96 @@
+package zabbix_test
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	zapi "github.com/claranet/go-zabbix-api"
+)
+
+func TestProblemsGet(t *testing.T) {
+	api := testFakeAPI(t, "7.0.0", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jsonrpc":"2.0","result":[
+			{"eventid":"42","source":"0","object":"0","objectid":"13","clock":"1700000000","ns":"500",
+				"r_eventid":"0","r_clock":"0","r_ns":"0","name":"CPU is busy","acknowledged":"0","severity":"5",
+				"suppressed":"0","opdata":"","urls":[],"tags":[{"tag":"service","value":"web"}]},
+			{"eventid":"43","source":"0","object":"0","objectid":"14","clock":"1700000100","ns":"0",
+				"r_eventid":"44","r_clock":"1700000200","r_ns":"7","name":"Disk is full","acknowledged":"1","severity":"2",
+				"suppressed":"1","opdata":""}
+		],"id":1}`))
+	})
+
+	problems, err := api.ProblemsGet(zapi.Params{"recent": true, "selectTags": "extend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 2 {
+		t.Fatalf("Expected 2 problems, got %d", len(problems))
+	}
+	p := problems[0]
+	if p.EventID != "42" || p.Severity != zapi.Critical || p.Acknowledged != 0 || !p.Clock.Equal(time.Unix(1700000000, 500)) || !p.RClock.IsZero() {
+		t.Errorf("Unexpected problem %#v", p)
+	}
+	if len(p.Tags) != 1 || p.Tags[0].Tag != "service" {
+		t.Errorf("Unexpected tags %#v", p.Tags)
+	}
+	p = problems[1]
+	if p.REventID != "44" || p.Severity != zapi.Warning || p.Suppressed != 1 || !p.RClock.Equal(time.Unix(1700000200, 7)) {
+		t.Errorf("Unexpected resolved problem %#v", p)
+	}
+}
+
+func TestProblemQuery(t *testing.T) {
+	var params map[string]interface{}
+	api := testFakeAPI(t, "7.0.0", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Params map[string]interface{} `json:"params"`
+		}
+		testDecodeRequest(r, &req)
+		params = req.Params
+		w.Write([]byte(`{"jsonrpc":"2.0","result":[],"id":1}`))
+	})
+
+	from, till := time.Unix(1700000000, 0), time.Unix(1700003600, 0)
+	q := zapi.NewProblemQuery().
+		Recent().
+		Severities(zapi.High, zapi.Critical).
+		Suppressed(false).
+		Acknowledged(false).
+		TimeFrom(from).
+		TimeTill(till).
+		Tags(zapi.TagOr,
+			zapi.TagFilter{Tag: "service", Value: "web", Operator: zapi.TagEquals},
+			zapi.TagFilter{Tag: "scope", Operator: zapi.TagExists})
+	q.Select("selectTags").Sort("eventid", zapi.SortDesc)
+	if _, err := api.ProblemsQuery(context.Background(), q); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"recent":       true,
+		"severities":   []interface{}{float64(4), float64(5)},
+		"suppressed":   false,
+		"acknowledged": false,
+		"time_from":    float64(from.Unix()),
+		"time_till":    float64(till.Unix()),
+		"evaltype":     float64(2),
+		"tags": []interface{}{
+			map[string]interface{}{"tag": "service", "value": "web", "operator": float64(1)},
+			map[string]interface{}{"tag": "scope", "value": "", "operator": float64(4)},
+		},
+		"output":     "extend",
+		"selectTags": "extend",
+		"sortfield":  []interface{}{"eventid"},
+		"sortorder":  []interface{}{"DESC"},
+	}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("Expected params %v, got %v", want, params)
+	}
+
+	if _, err := zapi.NewProblemQuery().Select("selectHosts").Params(); err == nil {
+		t.Error("Expected error for a select option of events")
+	}
+}
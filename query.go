@@ -0,0 +1,290 @@
+package zabbix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type (
+	// SortOrder is the direction of a sort field of *.get methods.
+	SortOrder string
+)
+
+const (
+	SortAsc  SortOrder = "ASC"
+	SortDesc SortOrder = "DESC"
+)
+
+// objectSelects lists the select* options of <object>.get by API methods prefix.
+var objectSelects = map[string][]string{
+	"action":           {"selectFilter", "selectOperations", "selectRecoveryOperations", "selectUpdateOperations", "selectAcknowledgeOperations"},
+	"application":      {"selectHost", "selectItems", "selectDiscoveryRule", "selectApplicationDiscovery"},
+	"discoveryrule":    {"selectFilter", "selectGraphs", "selectHostPrototypes", "selectHosts", "selectItems", "selectTriggers", "selectLLDMacroPaths", "selectPreprocessing", "selectOverrides"},
+	"host":             {"selectGroups", "selectHostGroups", "selectParentTemplates", "selectInterfaces", "selectItems", "selectTriggers", "selectGraphs", "selectMacros", "selectInventory", "selectTags", "selectInheritedTags", "selectDiscoveries", "selectDiscoveryRule", "selectHostDiscovery", "selectHttpTests", "selectApplications", "selectDashboards", "selectValueMaps"},
+	"event":            {"selectHosts", "selectRelatedObject", "select_alerts", "select_acknowledges", "selectTags", "selectSuppressionData"},
+	"hostgroup":        {"selectHosts", "selectTemplates", "selectDiscoveryRule", "selectDiscoveryRules", "selectGroupDiscovery", "selectGroupDiscoveries", "selectHostPrototypes"},
+	"item":             {"selectHosts", "selectInterfaces", "selectTriggers", "selectGraphs", "selectApplications", "selectDiscoveryRule", "selectItemDiscovery", "selectPreprocessing", "selectTags", "selectValueMap"},
+	"itemprototype":    {"selectDiscoveryRule", "selectGraphs", "selectHosts", "selectTriggers", "selectApplications", "selectApplicationPrototypes", "selectPreprocessing", "selectTags", "selectValueMap"},
+	"mediatype":        {"selectUsers", "selectMessageTemplates"},
+	"problem":          {"selectAcknowledges", "selectTags", "selectSuppressionData"},
+	"role":             {"selectRules", "selectUsers"},
+	"template":         {"selectGroups", "selectTemplateGroups", "selectHosts", "selectTemplates", "selectParentTemplates", "selectHttpTests", "selectItems", "selectDiscoveries", "selectTriggers", "selectGraphs", "selectApplications", "selectMacros", "selectDashboards", "selectTags", "selectValueMaps"},
+	"templategroup":    {"selectTemplates"},
+	"trigger":          {"selectGroups", "selectHostGroups", "selectHosts", "selectItems", "selectFunctions", "selectDependencies", "selectDiscoveryRule", "selectLastEvent", "selectTags", "selectTriggerDiscovery"},
+	"triggerprototype": {"selectDiscoveryRule", "selectFunctions", "selectGroups", "selectHostGroups", "selectHosts", "selectItems", "selectDependencies", "selectTags"},
+	"user":             {"selectMedias", "selectMediatypes", "selectUsrgrps", "selectRole"},
+	"usergroup":        {"selectTagFilters", "selectUsers", "selectRights", "selectHostGroupRights", "selectTemplateGroupRights"},
+	"usermacro":        {"selectGroups", "selectHosts", "selectTemplates"},
+}
+
+// GetQuery builds the parameters of <object>.get for the object type T.
+// Field names are checked against the json tags of T and select options against
+// the ones of the object, so misspelled names are reported by Params instead of
+// being ignored by the server.
+//
+//	params, err := zabbix.NewGetQuery[zabbix.Host]().
+//		Output("hostid", "host").
+//		Filter("status", "0").
+//		Select("selectInterfaces").
+//		Limit(10).
+//		Params()
+//	hosts, err := api.HostsGet(params)
+type GetQuery[T any] struct {
+	info   objectInfo
+	fields map[string]bool
+	params Params
+	errs   []error
+}
+
+// NewGetQuery Creates an empty query of T objects.
+// It panics if T is not an object type of this package.
+func NewGetQuery[T any]() *GetQuery[T] {
+	return &GetQuery[T]{
+		info:   NewRepository[T](nil).info,
+		fields: jsonFields(reflect.TypeOf((*T)(nil)).Elem()),
+		params: Params{},
+	}
+}
+
+// jsonFields returns the json names of the fields of struct type t.
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := map[string]bool{}
+	for name := range jsonFieldIndexes(t) {
+		fields[name] = true
+	}
+	return fields
+}
+
+// jsonFieldIndexes returns the indexes of the fields of struct type t by json name.
+func jsonFieldIndexes(t reflect.Type) map[string][]int {
+	fields := map[string][]int{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			for k, index := range jsonFieldIndexes(f.Type) {
+				fields[k] = append([]int{i}, index...)
+			}
+			continue
+		}
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = []int{i}
+	}
+	return fields
+}
+
+func (q *GetQuery[T]) checkFields(param string, fields ...string) bool {
+	ok := true
+	for _, field := range fields {
+		if !q.fields[field] {
+			q.errs = append(q.errs, fmt.Errorf("%w: %s %s: unknown field %q", ErrInvalidParams, q.info.prefix, param, field))
+			ok = false
+		}
+	}
+	return ok
+}
+
+// Output Sets the returned fields, all fields when none is given.
+func (q *GetQuery[T]) Output(fields ...string) *GetQuery[T] {
+	if len(fields) == 0 {
+		q.params["output"] = "extend"
+	} else if q.checkFields("output", fields...) {
+		q.params["output"] = fields
+	}
+	return q
+}
+
+// IDs Returns only the objects with the given IDs.
+func (q *GetQuery[T]) IDs(ids ...string) *GetQuery[T] {
+	q.params[q.info.idsParam] = ids
+	return q
+}
+
+// Filter Returns only the objects whose field exactly matches one of values.
+// field is not checked, as many filterable fields are not mapped on T, like "flags" for hosts.
+func (q *GetQuery[T]) Filter(field string, values ...string) *GetQuery[T] {
+	filter, _ := q.params["filter"].(map[string]interface{})
+	if filter == nil {
+		filter = map[string]interface{}{}
+		q.params["filter"] = filter
+	}
+	if len(values) == 1 {
+		filter[field] = values[0]
+	} else {
+		filter[field] = values
+	}
+	return q
+}
+
+// Search Returns only the objects whose field contains value. Like for Filter, field is not checked.
+func (q *GetQuery[T]) Search(field, value string) *GetQuery[T] {
+	search, _ := q.params["search"].(map[string]interface{})
+	if search == nil {
+		search = map[string]interface{}{}
+		q.params["search"] = search
+	}
+	search[field] = value
+	return q
+}
+
+// SearchWildcardsEnabled Allows "*" wildcards in Search values.
+func (q *GetQuery[T]) SearchWildcardsEnabled() *GetQuery[T] {
+	q.params["searchWildcardsEnabled"] = true
+	return q
+}
+
+// SearchByAny Returns objects matching any of the Filter or Search criteria instead of all of them.
+func (q *GetQuery[T]) SearchByAny() *GetQuery[T] {
+	q.params["searchByAny"] = true
+	return q
+}
+
+// StartSearch Matches Search values at the beginning of fields only.
+func (q *GetQuery[T]) StartSearch() *GetQuery[T] {
+	q.params["startSearch"] = true
+	return q
+}
+
+// Sort Sorts the result by field, after the previously given sort fields.
+func (q *GetQuery[T]) Sort(field string, order SortOrder) *GetQuery[T] {
+	if order != SortAsc && order != SortDesc {
+		q.errs = append(q.errs, fmt.Errorf("%w: %s sortorder: unknown order %q", ErrInvalidParams, q.info.prefix, order))
+		return q
+	}
+	if !q.checkFields("sortfield", field) {
+		return q
+	}
+	fields, _ := q.params["sortfield"].([]string)
+	orders, _ := q.params["sortorder"].([]SortOrder)
+	q.params["sortfield"] = append(fields, field)
+	q.params["sortorder"] = append(orders, order)
+	return q
+}
+
+// Limit Limits the number of returned objects.
+func (q *GetQuery[T]) Limit(n int) *GetQuery[T] {
+	q.params["limit"] = n
+	return q
+}
+
+// CountOutput Returns the number of objects instead of the objects.
+// Use Repository.Count to read the result.
+func (q *GetQuery[T]) CountOutput() *GetQuery[T] {
+	q.params["countOutput"] = true
+	return q
+}
+
+// Editable Returns only the objects the user has write permissions to.
+func (q *GetQuery[T]) Editable() *GetQuery[T] {
+	q.params["editable"] = true
+	return q
+}
+
+// PreserveKeys Indexes the result by object ID.
+// The result is then an object, which the *Get functions cannot parse.
+func (q *GetQuery[T]) PreserveKeys() *GetQuery[T] {
+	q.params["preservekeys"] = true
+	return q
+}
+
+// Select Sets the returned fields of the related objects of option, like
+// "selectInterfaces" for hosts, all fields when none is given.
+func (q *GetQuery[T]) Select(option string, fields ...string) *GetQuery[T] {
+	if !q.checkSelect(option) {
+		return q
+	}
+	if len(fields) == 0 {
+		q.params[option] = "extend"
+	} else {
+		q.params[option] = fields
+	}
+	return q
+}
+
+// SelectCount Returns the number of related objects of option instead of the objects.
+func (q *GetQuery[T]) SelectCount(option string) *GetQuery[T] {
+	if q.checkSelect(option) {
+		q.params[option] = "count"
+	}
+	return q
+}
+
+func (q *GetQuery[T]) checkSelect(option string) bool {
+	for _, s := range objectSelects[q.info.prefix] {
+		if s == option {
+			return true
+		}
+	}
+	q.errs = append(q.errs, fmt.Errorf("%w: %s: unknown option %q", ErrInvalidParams, q.info.prefix, option))
+	return false
+}
+
+// Set Sets any other parameter, like "hostids" for items, without checking it.
+func (q *GetQuery[T]) Set(name string, value interface{}) *GetQuery[T] {
+	q.params[name] = value
+	return q
+}
+
+// Params Returns the parameters of the query, or an error wrapping ErrInvalidParams
+// for every misspelled name.
+func (q *GetQuery[T]) Params() (Params, error) {
+	if len(q.errs) > 0 {
+		return nil, errors.Join(q.errs...)
+	}
+	params := make(Params, len(q.params))
+	for k, v := range q.params {
+		// Copy what later calls modify in place
+		switch v := v.(type) {
+		case map[string]interface{}:
+			c := make(map[string]interface{}, len(v))
+			for f, value := range v {
+				c[f] = value
+			}
+			params[k] = c
+		case []string:
+			params[k] = append([]string(nil), v...)
+		case []SortOrder:
+			params[k] = append([]SortOrder(nil), v...)
+		default:
+			params[k] = v
+		}
+	}
+	return params, nil
+}
+
+// Query Gets the objects matching q.
+func (r *Repository[T]) Query(ctx context.Context, q *GetQuery[T]) (res []T, err error) {
+	params, err := q.Params()
+	if err != nil {
+		return
+	}
+	return r.Get(ctx, params)
+}
@@ -0,0 +1,111 @@
+package zabbix_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	zapi "github.com/claranet/go-zabbix-api"
+)
+
+func TestGetQuery(t *testing.T) {
+	params, err := zapi.NewGetQuery[zapi.Host]().
+		Output("hostid", "host").
+		IDs("1", "2").
+		Filter("status", "0").
+		Filter("host", "a", "b").
+		Search("name", "web*").
+		SearchWildcardsEnabled().
+		Sort("name", zapi.SortDesc).
+		Sort("hostid", zapi.SortAsc).
+		Select("selectInterfaces").
+		Select("selectGroups", "groupid").
+		SelectCount("selectItems").
+		Limit(10).
+		Editable().
+		Params()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := zapi.Params{
+		"output":                 []string{"hostid", "host"},
+		"hostids":                []string{"1", "2"},
+		"filter":                 map[string]interface{}{"status": "0", "host": []string{"a", "b"}},
+		"search":                 map[string]interface{}{"name": "web*"},
+		"searchWildcardsEnabled": true,
+		"sortfield":              []string{"name", "hostid"},
+		"sortorder":              []zapi.SortOrder{zapi.SortDesc, zapi.SortAsc},
+		"selectInterfaces":       "extend",
+		"selectGroups":           []string{"groupid"},
+		"selectItems":            "count",
+		"limit":                  10,
+		"editable":               true,
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("Params:\n%#v\nexpected:\n%#v", params, expected)
+	}
+}
+
+func TestGetQueryMisspelled(t *testing.T) {
+	_, err := zapi.NewGetQuery[zapi.Host]().
+		Output("hostid", "hots").
+		Select("selectInterface").
+		Sort("name", "down").
+		Sort("stauts", zapi.SortAsc).
+		Params()
+	if !errors.Is(err, zapi.ErrInvalidParams) {
+		t.Fatalf("expected ErrInvalidParams, got %v", err)
+	}
+	for _, name := range []string{`"hots"`, `"selectInterface"`, `"down"`, `"stauts"`} {
+		if !strings.Contains(err.Error(), name) {
+			t.Errorf("error %q does not report %s", err, name)
+		}
+	}
+}
+
+func TestGetQueryUnmappedFilter(t *testing.T) {
+	params, err := zapi.NewGetQuery[zapi.Host]().
+		Filter("flags", "0").
+		Filter("maintenance_status", "1").
+		Search("proxy_hostid", "1").
+		Params()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := zapi.Params{
+		"filter": map[string]interface{}{"flags": "0", "maintenance_status": "1"},
+		"search": map[string]interface{}{"proxy_hostid": "1"},
+	}
+	if !reflect.DeepEqual(params, expected) {
+		t.Errorf("Params:\n%#v\nexpected:\n%#v", params, expected)
+	}
+}
+
+func TestGetQueryParamsCopy(t *testing.T) {
+	q := zapi.NewGetQuery[zapi.Host]().
+		Sort("name", zapi.SortDesc).
+		Sort("hostid", zapi.SortAsc).
+		Sort("host", zapi.SortAsc)
+	first, err := q.Params()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := append(first["sortfield"].([]string), "available")
+	orders := append(first["sortorder"].([]zapi.SortOrder), zapi.SortDesc)
+
+	second, err := q.Sort("status", zapi.SortAsc).Params()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fields[3] != "available" || orders[3] != zapi.SortDesc {
+		t.Errorf("Sort changed the params got before: %v %v", fields, orders)
+	}
+	if expected := []string{"name", "hostid", "host", "status"}; !reflect.DeepEqual(second["sortfield"], expected) {
+		t.Errorf("sortfield = %v, expected %v", second["sortfield"], expected)
+	}
+	if expected := []zapi.SortOrder{zapi.SortDesc, zapi.SortAsc, zapi.SortAsc, zapi.SortAsc}; !reflect.DeepEqual(second["sortorder"], expected) {
+		t.Errorf("sortorder = %v, expected %v", second["sortorder"], expected)
+	}
+}
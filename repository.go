@@ -0,0 +1,306 @@
+package zabbix
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// objectInfo describes how the API names and identifies one object type.
+type objectInfo struct {
+	prefix    string // API methods prefix, like "host" for host.get
+	idField   string // name of the Go struct field holding the ID, like "HostID"
+	idsParam  string // get parameter filtering by IDs, like "hostids"
+	resultKey string // key of the IDs returned by create, update and delete, like "hostids"
+}
+
+// objectInfos registers the object types usable with Repository.
+var objectInfos = map[reflect.Type]objectInfo{
+	reflect.TypeOf(Action{}):           {"action", "ActionID", "actionids", "actionids"},
+	reflect.TypeOf(Application{}):      {"application", "ApplicationID", "applicationids", "applicationids"},
+	reflect.TypeOf(Event{}):            {"event", "EventID", "eventids", "eventids"},
+	reflect.TypeOf(Host{}):             {"host", "HostID", "hostids", "hostids"},
+	reflect.TypeOf(HostGroup{}):        {"hostgroup", "GroupID", "groupids", "groupids"},
+	reflect.TypeOf(Item{}):             {"item", "ItemID", "itemids", "itemids"},
+	reflect.TypeOf(ItemPrototype{}):    {"itemprototype", "ItemID", "itemids", "itemids"},
+	reflect.TypeOf(LLDRule{}):          {"discoveryrule", "ItemID", "itemids", "itemids"},
+	reflect.TypeOf(Macro{}):            {"usermacro", "MacroID", "hostmacroids", "hostmacroids"},
+	reflect.TypeOf(MediaType{}):        {"mediatype", "MediaTypeID", "mediatypeids", "mediatypeids"},
+	reflect.TypeOf(ProblemEvent{}):     {"problem", "EventID", "eventids", "eventids"},
+	reflect.TypeOf(Role{}):             {"role", "RoleID", "roleids", "roleids"},
+	reflect.TypeOf(Template{}):         {"template", "TemplateID", "templateids", "templateids"},
+	reflect.TypeOf(TemplateGroup{}):    {"templategroup", "GroupID", "groupids", "groupids"},
+	reflect.TypeOf(Trigger{}):          {"trigger", "TriggerID", "triggerids", "triggerids"},
+	reflect.TypeOf(TriggerPrototype{}): {"triggerprototype", "TriggerID", "triggerids", "triggerids"},
+	reflect.TypeOf(User{}):             {"user", "UserID", "userids", "userids"},
+	reflect.TypeOf(UserGroup{}):        {"usergroup", "GroupID", "usrgrpids", "usrgrpids"},
+}
+
+// Repository gives the same operations to every Zabbix object type T, like Host, Item or Role.
+// It knows the API methods, ID field and result keys of each type.
+//
+//	roles := zabbix.NewRepository[zabbix.Role](api)
+//	role, err := roles.GetByID(ctx, "3")
+type Repository[T any] struct {
+	api  *API
+	info objectInfo
+}
+
+// NewRepository Creates a repository of T objects using api.
+// It panics if T is not an object type of this package.
+func NewRepository[T any](api *API) *Repository[T] {
+	info, ok := objectInfos[reflect.TypeOf((*T)(nil)).Elem()]
+	if !ok {
+		panic(fmt.Errorf("zabbix: %T is not a Zabbix object type", *new(T)))
+	}
+	return &Repository[T]{api: api, info: info}
+}
+
+func (r *Repository[T]) method(name string) string {
+	return r.info.prefix + "." + name
+}
+
+// Get Wrapper for <object>.get, output defaults to "extend".
+// IDs filtering the objects are sent in chunks of API.ChunkSize. The server sorts and limits
+// each chunk, so the merged results are sorted again by sortfield and cut to limit,
+// see mergeChunks. Sorting them by a field which is not on T returns an ErrInvalidParams error.
+func (r *Repository[T]) Get(ctx context.Context, params Params) (res []T, err error) {
+	if err = r.api.requireObject(ctx, r.info.prefix); err != nil {
+		return
+	}
+	p := make(Params, len(params)+1)
+	for k, v := range params {
+		p[k] = v
+	}
+	if _, present := p["output"]; !present {
+		p["output"] = "extend"
+	}
+	p = r.api.encodeGetParams(r.info.prefix, p)
+
+	ids, ok := idsParam(p, r.info.idsParam)
+	if !ok || ids.Kind() != reflect.Slice || !r.api.chunked(ids.Len()) {
+		return r.get(ctx, p)
+	}
+	// params has the names of the fields of T, p those of the server version
+	order, err := newChunkOrder(r.info.prefix, params, jsonFieldIndexes(reflect.TypeOf((*T)(nil)).Elem()))
+	if err != nil {
+		return
+	}
+	err = r.api.eachChunk(ids.Len(), func(lo, hi int) error {
+		p[r.info.idsParam] = ids.Slice(lo, hi).Interface()
+		chunk, err := r.get(ctx, p)
+		res = append(res, chunk...)
+		return err
+	})
+	if err != nil {
+		return
+	}
+	return mergeChunks(order, res), nil
+}
+
+// get calls <object>.get with p, decoding the fields of the server version.
+func (r *Repository[T]) get(ctx context.Context, p Params) (res []T, err error) {
+	var raw json.RawMessage
+	if err = r.api.CallWithErrorParseContext(ctx, r.method("get"), p, &raw); err != nil {
+		return
+	}
+	err = r.api.decodeObjects(r.info.prefix, raw, &res)
+	return
+}
+
+// GetByID Gets object by ID only if there is exactly 1 matching object.
+func (r *Repository[T]) GetByID(ctx context.Context, id string) (res *T, err error) {
+	return r.GetOne(ctx, Params{r.info.idsParam: id})
+}
+
+// GetOne Gets object matching params only if there is exactly 1 matching object.
+func (r *Repository[T]) GetOne(ctx context.Context, params Params) (res *T, err error) {
+	objects, err := r.Get(ctx, params)
+	if err != nil {
+		return
+	}
+
+	if len(objects) != 1 {
+		e := ExpectedOneResult(len(objects))
+		err = &e
+		return
+	}
+	res = &objects[0]
+	return
+}
+
+// Count Returns the number of objects matching params, using countOutput.
+func (r *Repository[T]) Count(ctx context.Context, params Params) (count int, err error) {
+	if err = r.api.requireObject(ctx, r.info.prefix); err != nil {
+		return
+	}
+	p := make(Params, len(params)+1)
+	for k, v := range params {
+		p[k] = v
+	}
+	p["countOutput"] = true
+	delete(p, "output")
+
+	var res interface{}
+	if err = r.api.CallWithErrorParseContext(ctx, r.method("get"), r.api.encodeGetParams(r.info.prefix, p), &res); err != nil {
+		return
+	}
+	switch res := res.(type) {
+	case string:
+		return strconv.Atoi(res)
+	case float64:
+		return int(res), nil
+	}
+	return 0, fmt.Errorf("zabbix: unexpected %s count result %v", r.info.prefix, res)
+}
+
+// Create Wrapper for <object>.create
+// Fills the ID of all objects if call succeed.
+// Objects are sent in chunks of API.ChunkSize, a *ChunkError tells how many were created.
+// Fields unknown to the server version are translated or dropped.
+func (r *Repository[T]) Create(ctx context.Context, objects []T) (err error) {
+	if err = r.api.requireObject(ctx, r.info.prefix); err != nil {
+		return
+	}
+	return r.api.eachChunk(len(objects), func(lo, hi int) error {
+		params, err := r.api.encodeObjects(r.info.prefix, objects[lo:hi])
+		if err != nil {
+			return err
+		}
+		response, err := r.api.CallWithErrorContext(ctx, r.method("create"), params)
+		if err != nil {
+			return err
+		}
+
+		ids, err := r.resultIDs(response.Result)
+		for i, id := range ids {
+			if lo+i < hi {
+				r.setID(&objects[lo+i], id)
+			}
+		}
+		return err
+	})
+}
+
+// Update Wrapper for <object>.update
+// Objects are sent in chunks of API.ChunkSize, a *ChunkError tells how many were updated.
+// Fields unknown to the server version are translated or dropped.
+func (r *Repository[T]) Update(ctx context.Context, objects []T) (err error) {
+	if err = r.api.requireObject(ctx, r.info.prefix); err != nil {
+		return
+	}
+	return r.api.eachChunk(len(objects), func(lo, hi int) error {
+		params, err := r.api.encodeObjects(r.info.prefix, objects[lo:hi])
+		if err != nil {
+			return err
+		}
+		_, err = r.api.CallWithErrorContext(ctx, r.method("update"), params)
+		return err
+	})
+}
+
+// Delete Wrapper for <object>.delete
+// Cleans the ID of all deleted objects.
+func (r *Repository[T]) Delete(ctx context.Context, objects []T) (err error) {
+	ids := make([]string, len(objects))
+	for i := range objects {
+		ids[i] = r.id(&objects[i])
+	}
+
+	err = r.DeleteByIDs(ctx, ids)
+	for i := 0; i < chunkDone(len(objects), err); i++ {
+		r.setID(&objects[i], "")
+	}
+	return
+}
+
+// DeleteByIDs Wrapper for <object>.delete
+// Returns an *ExpectedMore error if not all objects were deleted.
+// IDs are sent in chunks of API.ChunkSize, a *ChunkError tells how many were deleted.
+func (r *Repository[T]) DeleteByIDs(ctx context.Context, ids []string) (err error) {
+	deleted, err := r.deleteIDs(ctx, ids)
+	if err == nil && len(deleted) != len(ids) {
+		err = &ExpectedMore{len(ids), len(deleted)}
+	}
+	return
+}
+
+// deleteIDs calls <object>.delete with ids and returns the IDs of the deleted objects.
+func (r *Repository[T]) deleteIDs(ctx context.Context, ids []string) (deleted []string, err error) {
+	if err = r.api.requireObject(ctx, r.info.prefix); err != nil {
+		return
+	}
+	err = r.api.eachChunk(len(ids), func(lo, hi int) error {
+		response, err := r.api.CallWithErrorContext(ctx, r.method("delete"), ids[lo:hi])
+		if err != nil {
+			return err
+		}
+		chunk, err := r.resultIDs(response.Result)
+		deleted = append(deleted, chunk...)
+		return err
+	})
+	return
+}
+
+// resultIDs extracts IDs from the result of create, update and delete methods.
+// Some methods use their own key, like "prototypeids" for itemprototype.delete,
+// so the only key of the result is used when resultKey is missing.
+// Some Zabbix versions return IDs as numbers, or as an object instead of an array.
+func (r *Repository[T]) resultIDs(result interface{}) (ids []string, err error) {
+	m, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("zabbix: unexpected %s result %v", r.info.prefix, result)
+	}
+	raw, ok := m[r.info.resultKey]
+	if !ok && len(m) == 1 {
+		for _, v := range m {
+			raw = v
+		}
+	}
+
+	var values []interface{}
+	switch raw := raw.(type) {
+	case []interface{}:
+		values = raw
+	case map[string]interface{}:
+		// keys are the positions of the objects in the request
+		keys := make([]string, 0, len(raw))
+		for k := range raw {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			a, _ := strconv.Atoi(keys[i])
+			b, _ := strconv.Atoi(keys[j])
+			return a < b
+		})
+		for _, k := range keys {
+			values = append(values, raw[k])
+		}
+	default:
+		return nil, fmt.Errorf("zabbix: unexpected %s result %v", r.info.prefix, result)
+	}
+
+	ids = make([]string, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case string:
+			ids[i] = v
+		case float64:
+			ids[i] = strconv.FormatInt(int64(v), 10)
+		default:
+			return nil, fmt.Errorf("zabbix: unexpected %s id %v", r.info.prefix, v)
+		}
+	}
+	return
+}
+
+func (r *Repository[T]) id(object *T) string {
+	return reflect.ValueOf(object).Elem().FieldByName(r.info.idField).String()
+}
+
+func (r *Repository[T]) setID(object *T, id string) {
+	reflect.ValueOf(object).Elem().FieldByName(r.info.idField).SetString(id)
+}
@@ -0,0 +1,90 @@
+package zabbix_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	zapi "github.com/claranet/go-zabbix-api"
+)
+
+func TestRepository(t *testing.T) {
+	var calls []string
+	var lastParams map[string]interface{}
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string      `json:"method"`
+			Params interface{} `json:"params"`
+		}
+		testDecodeRequest(r, &req)
+		calls = append(calls, req.Method)
+		lastParams, _ = req.Params.(map[string]interface{})
+
+		var result string
+		switch req.Method {
+		case "role.create":
+			result = `{"roleids":["7","8"]}`
+		case "role.get":
+			if lastParams["countOutput"] == true {
+				result = `"2"`
+			} else {
+				result = `[]`
+			}
+		case "role.delete":
+			result = `{"roleids":["7","8"]}`
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","result":%s,"id":1}`, result)
+	})
+	ctx := context.Background()
+	roles := zapi.NewRepository[zapi.Role](api)
+
+	objects := []zapi.Role{{Name: "a"}, {Name: "b"}}
+	if err := roles.Create(ctx, objects); err != nil {
+		t.Fatal(err)
+	}
+	if objects[0].RoleID != "7" || objects[1].RoleID != "8" {
+		t.Errorf("Create did not set IDs: %#v", objects)
+	}
+
+	count, err := roles.Count(ctx, zapi.Params{"output": "extend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("Count = %d, want 2", count)
+	}
+	if _, present := lastParams["output"]; present {
+		t.Errorf("Count sent output: %v", lastParams)
+	}
+
+	_, err = roles.GetByID(ctx, "9")
+	if !errors.Is(err, zapi.ErrNotFound) {
+		t.Errorf("GetByID error = %v, want ErrNotFound", err)
+	}
+	if lastParams["roleids"] != "9" || lastParams["output"] != "extend" {
+		t.Errorf("GetByID params = %v", lastParams)
+	}
+
+	if err := roles.Delete(ctx, objects); err != nil {
+		t.Fatal(err)
+	}
+	if objects[0].RoleID != "" || objects[1].RoleID != "" {
+		t.Errorf("Delete did not clean IDs: %#v", objects)
+	}
+
+	want := []string{"role.create", "role.get", "role.get", "role.delete"}
+	if fmt.Sprint(calls) != fmt.Sprint(want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestRepositoryUnknownType(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewRepository did not panic for an unknown type")
+		}
+	}()
+	zapi.NewRepository[struct{}](nil)
+}
@@ -0,0 +1,119 @@
+package zabbix
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RetryClassifier reports whether a failed attempt of method is transient and may be retried.
+// status is 0 and body is nil when the request did not get any HTTP response.
+// For batch requests, method is the method of the first call of the batch.
+type RetryClassifier func(method string, status int, body []byte, err error) bool
+
+// RetryPolicy describes how API retries requests that failed transiently.
+// Read-only methods (*.get and apiinfo.version) are retried according to the policy,
+// methods which modify data are retried only if RetryMutating is set.
+// A batch is retried only if all of its calls may be retried.
+// The zero value disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values lower than 2 disable retries.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry. It doubles on every following retry.
+	BaseDelay time.Duration
+	// MaxDelay caps a single delay, zero means no cap.
+	MaxDelay time.Duration
+	// RetryMutating allows retrying methods like *.create, *.update or *.delete.
+	// Only enable it if replaying such a call is harmless for your use case.
+	RetryMutating bool
+	// Classifier decides which failures are transient, DefaultRetryClassifier is used when nil.
+	Classifier RetryClassifier
+}
+
+// DefaultRetryPolicy returns the policy used by NewAPI: 3 attempts of read-only methods,
+// starting at 200ms of backoff and never waiting more than 5s between attempts.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   200 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+	}
+}
+
+// DefaultRetryClassifier retries network errors, 502, 503 and 504 HTTP responses,
+// and empty or HTML bodies returned by a failing PHP frontend.
+// Canceled or expired contexts are never retried.
+func DefaultRetryClassifier(method string, status int, body []byte, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch status {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	body = bytes.TrimSpace(body)
+	return len(body) == 0 || body[0] == '<'
+}
+
+// isReadOnlyMethod reports whether method only reads data and is thus safe to replay.
+func isReadOnlyMethod(method string) bool {
+	method = strings.ToLower(method)
+	return strings.HasSuffix(method, ".get") || method == "apiinfo.version"
+}
+
+// attempts returns the number of attempts allowed for a request made of methods.
+func (p RetryPolicy) attempts(methods ...string) int {
+	if p.MaxAttempts < 2 {
+		return 1
+	}
+	for _, method := range methods {
+		if !p.RetryMutating && !isReadOnlyMethod(method) {
+			return 1
+		}
+	}
+	return p.MaxAttempts
+}
+
+func (p RetryPolicy) retryable(method string, status int, body []byte, err error) bool {
+	classifier := p.Classifier
+	if classifier == nil {
+		classifier = DefaultRetryClassifier
+	}
+	return classifier(method, status, body, err)
+}
+
+// backoff returns the delay before the retry following attempt, jittered between half and all of it.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,70 @@
+package zabbix_test
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	zapi "github.com/claranet/go-zabbix-api"
+)
+
+func testFlakyAPI(t *testing.T, failures int32, calls *int32) *zapi.API {
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(calls, 1) <= failures {
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("<html>502 Bad Gateway</html>"))
+			return
+		}
+		w.Write([]byte(`{"jsonrpc":"2.0","result":{"hostids":["1"]},"id":1}`))
+	})
+	api.Retry.BaseDelay = time.Millisecond
+	return api
+}
+
+func TestRetryReadOnly(t *testing.T) {
+	var calls int32
+	api := testFlakyAPI(t, 2, &calls)
+
+	if _, err := api.CallWithError("host.get", zapi.Params{}); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestRetryMutating(t *testing.T) {
+	var calls int32
+	api := testFlakyAPI(t, 2, &calls)
+
+	if _, err := api.CallWithError("host.delete", []string{"1"}); err == nil {
+		t.Error("Expected mutating call to fail without retries")
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 attempt, got %d", calls)
+	}
+
+	api.Retry.RetryMutating = true
+	if _, err := api.CallWithError("host.delete", []string{"1"}); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts in total, got %d", calls)
+	}
+}
+
+func TestRetryClassifier(t *testing.T) {
+	var calls int32
+	api := testFlakyAPI(t, 2, &calls)
+	api.Retry.Classifier = func(method string, status int, body []byte, err error) bool {
+		return false
+	}
+
+	if _, err := api.CallWithError("host.get", zapi.Params{}); err == nil {
+		t.Error("Expected call to fail without retries")
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 attempt, got %d", calls)
+	}
+}
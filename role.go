@@ -1,5 +1,7 @@
 package zabbix
 
+import "context"
+
 type (
 	// Role type.
 	// "type" in https://www.zabbix.com/documentation/current/manual/api/reference/role/object
@@ -27,9 +29,10 @@ type Roles []Role
 // RoleGet Wrapper for role.get
 // https://www.zabbix.com/documentation/current/manual/api/reference/role/get
 func (api *API) RolesGet(params Params) (res Roles, err error) {
-	if _, present := params["output"]; !present {
-		params["output"] = "extend"
-	}
-	err = api.CallWithErrorParse("role.get", params, &res)
-	return
+	return api.RolesGetContext(context.Background(), params)
+}
+
+// RolesGetContext is like RolesGet but uses ctx for the underlying requests.
+func (api *API) RolesGetContext(ctx context.Context, params Params) (res Roles, err error) {
+	return NewRepository[Role](api).Get(ctx, params)
 }
@@ -7,6 +7,8 @@ import (
 )
 
 func TestRoleGet(t *testing.T) {
+	skipTestIfVersionLessThan(t, "5.2", "introduced support for Role API")
+
 	api := testGetAPI(t)
 
 	params := zapi.Params{}
@@ -36,6 +38,8 @@ func TestRoleGet(t *testing.T) {
 }
 
 func TestRoleGetWithFilter(t *testing.T) {
+	skipTestIfVersionLessThan(t, "5.2", "introduced support for Role API")
+
 	api := testGetAPI(t)
 
 	// Test filter by type (User type = 1)
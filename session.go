@@ -0,0 +1,187 @@
+package zabbix
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CredentialSource provides the user and password used to log in again when the session expired.
+// It is called each time a new session is needed, so it may return rotated secrets.
+type CredentialSource interface {
+	Credentials(ctx context.Context) (user, password string, err error)
+}
+
+// StaticCredentials is a CredentialSource always returning the same user and password.
+type StaticCredentials struct {
+	User     string
+	Password string
+}
+
+// Credentials implements CredentialSource.
+func (c StaticCredentials) Credentials(ctx context.Context) (user, password string, err error) {
+	return c.User, c.Password, nil
+}
+
+// LoginWithCredentials Calls "user.login" API method with credentials from src and fills api.Auth field.
+// src is kept to log in again and replay the request once when the session expires,
+// for example after the frontend autologout or when the session was killed.
+func (api *API) LoginWithCredentials(src CredentialSource) (auth string, err error) {
+	return api.LoginWithCredentialsContext(context.Background(), src)
+}
+
+// LoginWithCredentialsContext is like LoginWithCredentials but uses ctx for the HTTP requests.
+func (api *API) LoginWithCredentialsContext(ctx context.Context, src CredentialSource) (auth string, err error) {
+	user, password, err := src.Credentials(ctx)
+	if err != nil {
+		return
+	}
+	auth, err = api.login(ctx, user, password)
+	if err == nil {
+		api.setCredentials(src)
+	}
+	return
+}
+
+// shouldRelogin reports whether the response b to method says the session expired
+// and api holds credentials to open a new one.
+func (api *API) shouldRelogin(method string, b []byte) bool {
+	if api.credentialSource() == nil || strings.EqualFold(method, "user.login") || strings.EqualFold(method, "user.logout") {
+		return false
+	}
+	var response RawResponse
+	if json.Unmarshal(b, &response) != nil || response.Error == nil {
+		return false
+	}
+	return errors.Is(response.Error, ErrSessionExpired)
+}
+
+// relogin logs in again with the remembered credentials, unless another call
+// already replaced the stale auth token while we were waiting for the lock.
+func (api *API) relogin(ctx context.Context, stale string) error {
+	api.reloginMu.Lock()
+	defer api.reloginMu.Unlock()
+
+	if api.authToken() != stale {
+		return nil
+	}
+	user, password, err := api.credentialSource().Credentials(ctx)
+	if err != nil {
+		return err
+	}
+	api.printf("Session expired, logging in again as %s", user)
+	_, err = api.login(ctx, user, password)
+	return err
+}
+
+// Logout Calls "user.logout" API method to close the session opened by Login, then forgets it
+// along with the credentials kept to log in again. It does nothing without a session, like with
+// API tokens, and a session which already expired is not an error.
+func (api *API) Logout() (err error) {
+	return api.LogoutContext(context.Background())
+}
+
+// LogoutContext is like Logout but uses ctx for the HTTP request.
+func (api *API) LogoutContext(ctx context.Context) (err error) {
+	api.mu.RLock()
+	auth, session := api.Auth, api.session
+	api.mu.RUnlock()
+	if auth == "" || !session {
+		return nil
+	}
+
+	_, err = api.CallWithErrorContext(ctx, "user.logout", []string{})
+	if err != nil && !errors.Is(err, ErrSessionExpired) {
+		return
+	}
+	api.mu.Lock()
+	if api.Auth == auth {
+		api.Auth, api.session = "", false
+	}
+	api.credentials = nil
+	api.mu.Unlock()
+	return nil
+}
+
+// Session is the user of a session or API token, returned by CheckAuthentication.
+type Session struct {
+	User
+	SessionID string        `json:"sessionid"`
+	UserIP    string        `json:"userip"`
+	DebugMode DebugModeType `json:"debug_mode,string"`
+	GuiAccess int           `json:"gui_access,string"`
+
+	// Expires is when the session ends without activity, after the user autologout.
+	// It is zero for API tokens and sessions without autologout.
+	Expires time.Time `json:"-"`
+}
+
+// CheckAuthentication Calls "user.checkAuthentication" API method with the auth token, prolonging the session.
+// API tokens are checked from Zabbix 6.4, older servers only check sessions.
+func (api *API) CheckAuthentication() (res *Session, err error) {
+	return api.CheckAuthenticationContext(context.Background())
+}
+
+// CheckAuthenticationContext is like CheckAuthentication but uses ctx for the HTTP request.
+func (api *API) CheckAuthenticationContext(ctx context.Context) (res *Session, err error) {
+	if err = api.ensureServerVersion(ctx); err != nil {
+		return
+	}
+	api.mu.RLock()
+	auth, session := api.Auth, api.session
+	api.mu.RUnlock()
+
+	params := Params{"sessionid": auth}
+	if !session && api.Supports(FeatureBearerAuth) {
+		params = Params{"token": auth}
+	}
+	// The method takes the token in its params, not as authentication
+	start := time.Now()
+	var raw json.RawMessage
+	if err = api.CallWithErrorParseContext(withoutAuth(ctx), "user.checkAuthentication", params, &raw); err != nil {
+		return
+	}
+	if err = api.decodeObjects("user", raw, &res); err != nil {
+		return
+	}
+	if res == nil {
+		return nil, fmt.Errorf("zabbix: unexpected user.checkAuthentication result %s", raw)
+	}
+	if autologout, ok := parseSeconds(res.Autologout); session && ok && autologout > 0 {
+		res.Expires = start.Add(autologout)
+	}
+	return
+}
+
+// Close Logs out if Login was used, and closes the idle connections of the HTTP client.
+// The API may still be used afterwards, logging in again.
+func (api *API) Close() (err error) {
+	return api.CloseContext(context.Background())
+}
+
+// CloseContext is like Close but uses ctx for the HTTP request.
+func (api *API) CloseContext(ctx context.Context) (err error) {
+	err = api.LogoutContext(ctx)
+	api.httpClient().CloseIdleConnections()
+	return
+}
+
+// parseSeconds parses a Zabbix time period, like "15m", "1h" or "900" seconds.
+func parseSeconds(period string) (d time.Duration, ok bool) {
+	units := map[byte]time.Duration{'s': time.Second, 'm': time.Minute, 'h': time.Hour, 'd': 24 * time.Hour, 'w': 7 * 24 * time.Hour}
+	unit := time.Second
+	if n := len(period); n > 0 {
+		if u, suffixed := units[period[n-1]]; suffixed {
+			period, unit = period[:n-1], u
+		}
+	}
+	n, err := strconv.Atoi(period)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
@@ -0,0 +1,172 @@
+package zabbix_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	zapi "github.com/claranet/go-zabbix-api"
+	"github.com/claranet/go-zabbix-api/zabbixtest"
+)
+
+// testSessionServer fakes user.login sessions, expire() terminates the current one.
+type testSessionServer struct {
+	mu      sync.Mutex
+	logins  int
+	session string
+}
+
+func (s *testSessionServer) expire() {
+	s.mu.Lock()
+	s.session = ""
+	s.mu.Unlock()
+}
+
+func (s *testSessionServer) handle(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Method string `json:"method"`
+		Auth   string `json:"auth"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+	auth := req.Auth
+	if bearer := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "); bearer != "" {
+		auth = bearer
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch {
+	case req.Method == "user.login" && auth != "":
+		w.Write([]byte(`{"jsonrpc":"2.0","error":{"code":-32602,"message":"Invalid params.","data":"Invalid parameter \"/\": unexpected parameter \"auth\"."},"id":1}`))
+	case req.Method == "user.login":
+		s.logins++
+		s.session = fmt.Sprintf("session-%d", s.logins)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","result":%q,"id":1}`, s.session)
+	case auth == "" || auth != s.session:
+		w.Write([]byte(`{"jsonrpc":"2.0","error":{"code":-32602,"message":"Invalid params.","data":"Session terminated, re-login, please."},"id":1}`))
+	default:
+		w.Write([]byte(`{"jsonrpc":"2.0","result":[],"id":1}`))
+	}
+}
+
+func TestRelogin(t *testing.T) {
+	// The expired session is sent in the auth field before 6.4, and in the Authorization header after
+	for _, version := range []string{"6.0.0", "6.4.0"} {
+		srv := &testSessionServer{}
+		api := testFakeAPI(t, version, srv.handle)
+
+		if _, err := api.LoginWithCredentials(zapi.StaticCredentials{User: "Admin", Password: "zabbix"}); err != nil {
+			t.Fatal(err)
+		}
+		srv.expire()
+
+		if _, err := api.HostsGet(zapi.Params{}); err != nil {
+			t.Fatalf("%s: %s", version, err)
+		}
+		if srv.logins != 2 || api.Auth != "session-2" {
+			t.Errorf("%s: expected a second login, got %d logins and auth %q", version, srv.logins, api.Auth)
+		}
+	}
+}
+
+func TestReloginDisabled(t *testing.T) {
+	srv := &testSessionServer{}
+	api := testFakeAPI(t, "6.0.0", srv.handle)
+
+	if _, err := api.Login("Admin", "zabbix"); err != nil {
+		t.Fatal(err)
+	}
+	srv.expire()
+
+	if _, err := api.HostsGet(zapi.Params{}); err == nil {
+		t.Error("Expected session error without remembered credentials")
+	}
+	if srv.logins != 1 {
+		t.Errorf("Expected 1 login, got %d", srv.logins)
+	}
+
+	api.RememberCredentials = true
+	if _, err := api.Login("Admin", "zabbix"); err != nil {
+		t.Fatal(err)
+	}
+	srv.expire()
+	if _, err := api.HostsGet(zapi.Params{}); err != nil {
+		t.Fatal(err)
+	}
+	if srv.logins != 3 {
+		t.Errorf("Expected 3 logins, got %d", srv.logins)
+	}
+}
+
+func TestLogout(t *testing.T) {
+	srv, api := testServerAPI(t, "7.0.0")
+	api.RememberCredentials = true
+	if _, err := api.Login(zabbixtest.AdminUser, zabbixtest.AdminPassword); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := api.Logout(); err != nil {
+		t.Fatal(err)
+	}
+	if api.Auth != "" {
+		t.Errorf("Expected no auth after logout, got %q", api.Auth)
+	}
+	if _, err := api.HostsGet(zapi.Params{}); !errors.Is(err, zapi.ErrSessionExpired) {
+		t.Errorf("Expected ErrSessionExpired without a new login, got %v", err)
+	}
+	if err := api.Logout(); err != nil {
+		t.Errorf("Expected no error without a session, got %v", err)
+	}
+
+	// Close logs out sessions, not API tokens
+	if _, err := api.Login(zabbixtest.AdminUser, zabbixtest.AdminPassword); err != nil {
+		t.Fatal(err)
+	}
+	other, err := zapi.NewAPI(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.SetToken(api.Auth)
+	if err = other.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = api.HostsGet(zapi.Params{}); err != nil {
+		t.Errorf("Expected Close not to log out API tokens, got %v", err)
+	}
+	if err = api.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err = api.Close(); err != nil {
+		t.Errorf("Expected no error closing twice, got %v", err)
+	}
+}
+
+func TestCheckAuthentication(t *testing.T) {
+	for _, version := range []string{"5.0.30", "7.0.0"} {
+		_, api := testServerAPI(t, version)
+
+		start := time.Now()
+		session, err := api.CheckAuthentication()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if session.Username != zabbixtest.AdminUser || session.UserID == "" || session.SessionID != api.Auth {
+			t.Errorf("%s: unexpected session %#v", version, session)
+		}
+		if expires := start.Add(15 * time.Minute); session.Expires.Before(expires) || session.Expires.After(expires.Add(time.Minute)) {
+			t.Errorf("%s: expected expiry after 15m, got %v", version, session.Expires)
+		}
+
+		if err = api.Logout(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = api.CheckAuthentication(); !errors.Is(err, zapi.ErrSessionExpired) {
+			t.Errorf("%s: expected ErrSessionExpired after logout, got %v", version, err)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package zabbix
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Stream reads the objects returned by a get method one at a time. They are decoded
+// from the HTTP response as they arrive, so the whole result is never held in memory.
+// Like sql.Rows, a Stream must be closed and Err reports the error which stopped Next.
+//
+//	items := api.ItemsIter(ctx, zabbix.Params{"hostids": hostIDs})
+//	defer items.Close()
+//	for items.Next() {
+//		item := items.Value()
+//		...
+//	}
+//	if err := items.Err(); err != nil {
+//		...
+//	}
+type Stream[T any] struct {
+	ctx   context.Context
+	span  Span
+	body  io.Closer
+	dec   *json.Decoder
+	value T
+	err   error
+
+	// decode decodes each element instead of the decoder when set
+	decode func(raw json.RawMessage, v *T) error
+}
+
+// NewStream Calls method with params and returns a Stream of the elements of its result array.
+// The call goes through the interceptors, see API.Use. The request is retried and the session
+// renewed as for other calls until the result starts to arrive, not after.
+func NewStream[T any](ctx context.Context, api *API, method string, params interface{}) *Stream[T] {
+	s := &Stream[T]{}
+	s.ctx, s.span = api.startSpan(ctx, method, params)
+	response, err := api.chain(func(ctx context.Context, method string, params interface{}) ([]byte, error) {
+		if s.body != nil {
+			// called again by an interceptor
+			s.body.Close()
+		}
+		var err error
+		s.body, s.dec, err = api.openStream(ctx, method, params)
+		var apiErr *Error
+		if errors.As(err, &apiErr) {
+			// API errors are part of the response, as for other calls
+			return json.Marshal(RawResponse{Jsonrpc: "2.0", Error: apiErr})
+		}
+		return nil, err
+	})(s.ctx, method, params)
+
+	switch {
+	case err != nil:
+		s.fail(err)
+	case response != nil:
+		// the response of an API error or of an interceptor
+		if s.body != nil {
+			s.body.Close()
+			s.body = nil
+		}
+		if s.dec, err = openResult(method, bytes.NewReader(response)); err != nil {
+			s.fail(err)
+		}
+	case s.dec == nil:
+		s.fail(fmt.Errorf("zabbix: no %s response from the interceptors", method))
+	}
+	return s
+}
+
+// Next Decodes the next object, returning false at the end of the result or on error.
+func (s *Stream[T]) Next() bool {
+	if s.dec == nil {
+		return false
+	}
+	if !s.dec.More() {
+		// consume the closing bracket
+		_, err := s.dec.Token()
+		s.fail(err)
+		return false
+	}
+
+	var value T
+	var err error
+	if s.decode != nil {
+		var raw json.RawMessage
+		if err = s.dec.Decode(&raw); err == nil {
+			err = s.decode(raw, &value)
+		}
+	} else {
+		err = s.dec.Decode(&value)
+	}
+	if err != nil {
+		s.fail(err)
+		return false
+	}
+	s.value = value
+	return true
+}
+
+func (s *Stream[T]) fail(err error) {
+	if err != nil {
+		// Report cancellation and deadlines as the bare context error, like calls do
+		if ctxErr := s.ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		}
+		s.err = err
+	}
+	s.Close()
+}
+
+// Value Returns the object decoded by the last call to Next.
+func (s *Stream[T]) Value() T {
+	return s.value
+}
+
+// Err Returns the error which stopped Next, if any.
+func (s *Stream[T]) Err() error {
+	return s.err
+}
+
+// Close Closes the HTTP response, Next then returns false.
+// It is safe to call Close several times.
+func (s *Stream[T]) Close() error {
+	s.dec = nil
+	if s.span != nil {
+		endSpan(s.span, nil, s.err)
+		s.span = nil
+	}
+	if s.body == nil {
+		return nil
+	}
+	body := s.body
+	s.body = nil
+	return body.Close()
+}
+
+// Iter Returns a Stream of the objects matching params, output defaults to "extend".
+// Fields are translated for the server version like Get does.
+func (r *Repository[T]) Iter(ctx context.Context, params Params) *Stream[T] {
+	if err := r.api.requireObject(ctx, r.info.prefix); err != nil {
+		return &Stream[T]{ctx: ctx, err: err}
+	}
+	p := make(Params, len(params)+1)
+	for k, v := range params {
+		p[k] = v
+	}
+	if _, present := p["output"]; !present {
+		p["output"] = "extend"
+	}
+	s := NewStream[T](ctx, r.api, r.method("get"), r.api.encodeGetParams(r.info.prefix, p))
+	if len(fieldChanges[r.info.prefix]) > 0 {
+		s.decode = func(raw json.RawMessage, v *T) error {
+			return r.api.decodeObjects(r.info.prefix, raw, v)
+		}
+	}
+	return s
+}
+
+// openStream calls method and returns the response body and a decoder positioned on the
+// first element of the result array. It renews the session like do.
+func (api *API) openStream(ctx context.Context, method string, params interface{}) (body io.Closer, dec *json.Decoder, err error) {
+	auth := api.authToken()
+	if auth != "" {
+		// The server version tells how to send auth
+		if err = api.ensureServerVersion(ctx); err != nil {
+			return
+		}
+	}
+	body, dec, err = api.sendStream(ctx, method, params, auth)
+	if auth == "" || api.credentialSource() == nil || !errors.Is(err, ErrSessionExpired) {
+		return
+	}
+	if err = api.relogin(ctx, auth); err != nil {
+		return
+	}
+	return api.sendStream(ctx, method, params, api.authToken())
+}
+
+// sendStream is like send but keeps the response body open for decoding.
+// Failed responses are read as a whole, they are small.
+func (api *API) sendStream(ctx context.Context, method string, params interface{}, auth string) (body io.Closer, dec *json.Decoder, err error) {
+	ex, err := api.newExchange(method, params, auth)
+	if err != nil {
+		return
+	}
+	setRequestID(ctx, ex.ids[0])
+
+	api.printBody(ex.body, false, "Request (POST)")
+	attempts := api.Retry.attempts(method)
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		res, err := api.roundTrip(ctx, ex)
+		var status int
+		var b []byte
+		if err == nil {
+			status = res.StatusCode
+			r := bufio.NewReader(res.Body)
+			if first, _ := r.Peek(1); status == http.StatusOK && len(first) == 1 && first[0] == '{' {
+				api.printf("Response (%d): streamed", status)
+				dec, err = openResult(method, r)
+				if ctxErr := ctx.Err(); err != nil && ctxErr != nil {
+					err = ctxErr
+				}
+				api.observe(ctx, ex, attempt, status, nil, time.Since(start), err)
+				if err != nil {
+					res.Body.Close()
+					return nil, nil, err
+				}
+				return res.Body, dec, nil
+			}
+
+			b, err = io.ReadAll(r)
+			res.Body.Close()
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				err = ctxErr
+			}
+			api.printBody(b, false, "Response (%d)", status)
+		}
+		api.observe(ctx, ex, attempt, status, b, time.Since(start), err)
+
+		if attempt >= attempts || !api.Retry.retryable(method, status, b, err) {
+			if err == nil {
+				err = fmt.Errorf("zabbix: unexpected %s response (HTTP %d): %.100s", method, status, strings.TrimSpace(string(b)))
+			}
+			return nil, nil, err
+		}
+		api.printf("Retrying %s (attempt %d of %d)", method, attempt+1, attempts)
+		if err = sleepContext(ctx, api.Retry.backoff(attempt)); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// openResult reads the JSON-RPC response from r up to the first element of its result array.
+// It returns the *Error of the response instead, if any.
+func openResult(method string, r io.Reader) (dec *json.Decoder, err error) {
+	if dec, err = openResponse(method, r); err != nil {
+		return nil, err
+	}
+	if err = expectDelim(dec, '['); err != nil {
+		return nil, fmt.Errorf("zabbix: %s result is not an array: %w", method, err)
+	}
+	return dec, nil
+}
+
+// openResponse reads the JSON-RPC response from r up to its result, of any type.
+// It returns the *Error of the response instead, if any.
+func openResponse(method string, r io.Reader) (dec *json.Decoder, err error) {
+	dec = json.NewDecoder(r)
+	if err = expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch key {
+		case "result":
+			return dec, nil
+		case "error":
+			var e Error
+			if err = dec.Decode(&e); err != nil {
+				return nil, err
+			}
+			return nil, &e
+		default:
+			var skip json.RawMessage
+			if err = dec.Decode(&skip); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return nil, fmt.Errorf("zabbix: %s response has no result", method)
+}
+
+// expectDelim reads the next token of dec, which must be delim.
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if t != delim {
+		return fmt.Errorf("expected %q, got %v", delim, t)
+	}
+	return nil
+}
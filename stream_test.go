@@ -0,0 +1,111 @@
+package zabbix_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	zapi "github.com/claranet/go-zabbix-api"
+)
+
+func TestItemsIter(t *testing.T) {
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"jsonrpc":"2.0","result":[{"itemid":"1","name":"a"},{"itemid":"2","name":"b"}],"id":1}`)
+	})
+
+	items := api.ItemsIter(context.Background(), zapi.Params{})
+	defer items.Close()
+	var names []string
+	for items.Next() {
+		names = append(names, items.Value().Name)
+	}
+	if err := items.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(names, []string{"a", "b"}) {
+		t.Errorf("names = %v", names)
+	}
+}
+
+func TestItemsIterError(t *testing.T) {
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"jsonrpc":"2.0","error":{"code":-32602,"message":"Invalid params.","data":"Invalid parameter \"/\": unexpected parameter \"foo\"."},"id":1}`)
+	})
+
+	items := api.ItemsIter(context.Background(), zapi.Params{})
+	defer items.Close()
+	if items.Next() {
+		t.Error("Next returned true on an error response")
+	}
+	if !errors.Is(items.Err(), zapi.ErrInvalidParams) {
+		t.Errorf("Err = %v, want ErrInvalidParams", items.Err())
+	}
+}
+
+func TestItemsIterTruncated(t *testing.T) {
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"jsonrpc":"2.0","result":[{"itemid":"1"},{"itemid":`)
+	})
+
+	items := api.ItemsIter(context.Background(), zapi.Params{})
+	defer items.Close()
+	n := 0
+	for items.Next() {
+		n++
+	}
+	if n != 1 || items.Err() == nil {
+		t.Errorf("got %d items and error %v, want 1 item and an error", n, items.Err())
+	}
+}
+
+func TestPager(t *testing.T) {
+	var requests []map[string]interface{}
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Params map[string]interface{} `json:"params"`
+		}
+		testDecodeRequest(r, &req)
+		requests = append(requests, req.Params)
+
+		if ids, ok := req.Params["hostids"].([]interface{}); ok {
+			hosts := []zapi.Host{}
+			for _, id := range ids {
+				hosts = append(hosts, zapi.Host{HostID: id.(string)})
+			}
+			b, _ := json.Marshal(hosts)
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","result":%s,"id":1}`, b)
+			return
+		}
+		fmt.Fprint(w, `{"jsonrpc":"2.0","result":[{"hostid":"1"},{"hostid":"2"},{"hostid":"3"}],"id":1}`)
+	})
+
+	pager := zapi.NewRepository[zapi.Host](api).Pager(zapi.Params{"groupids": "4", "selectInterfaces": "extend"}, 2)
+	var pages [][]string
+	for pager.Next(context.Background()) {
+		var ids []string
+		for _, host := range pager.Page() {
+			ids = append(ids, host.HostID)
+		}
+		pages = append(pages, ids)
+	}
+	if err := pager.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]string{{"1", "2"}, {"3"}}; !reflect.DeepEqual(pages, want) {
+		t.Errorf("pages = %v, want %v", pages, want)
+	}
+
+	if len(requests) != 3 {
+		t.Fatalf("got %d requests, want 3", len(requests))
+	}
+	if _, present := requests[0]["selectInterfaces"]; present || requests[0]["sortfield"] != "hostid" {
+		t.Errorf("IDs request params = %v", requests[0])
+	}
+	if requests[2]["limit"] != float64(1) || requests[2]["groupids"] != "4" || requests[2]["selectInterfaces"] != "extend" {
+		t.Errorf("page request params = %v", requests[2])
+	}
+}
@@ -1,5 +1,7 @@
 package zabbix
 
+import "context"
+
 // TemplateGroup represent Zabbix template group object, new in v6.2
 // https://www.zabbix.com/documentation/6.2/en/manual/api/reference/templategroup/object
 type TemplateGroup struct {
@@ -13,83 +15,66 @@ type TemplateGroups []TemplateGroup
 // TemplateGroupsGet Wrapper for templategroup.get
 // https://www.zabbix.com/documentation/6.2/en/manual/api/reference/templategroup/get
 func (api *API) TemplateGroupsGet(params Params) (res TemplateGroups, err error) {
-	if _, present := params["output"]; !present {
-		params["output"] = "extend"
-	}
-	err = api.CallWithErrorParse("templategroup.get", params, &res)
-	return
+	return api.TemplateGroupsGetContext(context.Background(), params)
+}
+
+// TemplateGroupsGetContext is like TemplateGroupsGet but uses ctx for the underlying requests.
+func (api *API) TemplateGroupsGetContext(ctx context.Context, params Params) (res TemplateGroups, err error) {
+	return NewRepository[TemplateGroup](api).Get(ctx, params)
 }
 
 // TemplateGroupGetByID Gets host group by Id only if there is exactly 1 matching host group.
 // https://www.zabbix.com/documentation/6.2/en/manual/api/reference/templategroup/get
 func (api *API) TemplateGroupGetByID(id string) (res *TemplateGroup, err error) {
-	groups, err := api.TemplateGroupsGet(Params{"groupids": id})
-	if err != nil {
-		return
-	}
+	return api.TemplateGroupGetByIDContext(context.Background(), id)
+}
 
-	if len(groups) == 1 {
-		res = &groups[0]
-	} else {
-		e := ExpectedOneResult(len(groups))
-		err = &e
-	}
-	return
+// TemplateGroupGetByIDContext is like TemplateGroupGetByID but uses ctx for the underlying requests.
+func (api *API) TemplateGroupGetByIDContext(ctx context.Context, id string) (res *TemplateGroup, err error) {
+	return NewRepository[TemplateGroup](api).GetByID(ctx, id)
 }
 
 // TemplateGroupsCreate Wrapper for templategroup.create
 // https://www.zabbix.com/documentation/6.2/en/manual/api/reference/templategroup/create
 func (api *API) TemplateGroupsCreate(TemplateGroups TemplateGroups) (err error) {
-	response, err := api.CallWithError("templategroup.create", TemplateGroups)
-	if err != nil {
-		return
-	}
+	return api.TemplateGroupsCreateContext(context.Background(), TemplateGroups)
+}
 
-	result := response.Result.(map[string]interface{})
-	groupids := result["groupids"].([]interface{})
-	for i, id := range groupids {
-		TemplateGroups[i].GroupID = id.(string)
-	}
-	return
+// TemplateGroupsCreateContext is like TemplateGroupsCreate but uses ctx for the underlying requests.
+func (api *API) TemplateGroupsCreateContext(ctx context.Context, TemplateGroups TemplateGroups) (err error) {
+	return NewRepository[TemplateGroup](api).Create(ctx, TemplateGroups)
 }
 
 // TemplateGroupsUpdate Wrapper for templategroup.update
 // https://www.zabbix.com/documentation/6.2/en/manual/api/reference/templategroup/update
 func (api *API) TemplateGroupsUpdate(TemplateGroups TemplateGroups) (err error) {
-	_, err = api.CallWithError("templategroup.update", TemplateGroups)
-	return
+	return api.TemplateGroupsUpdateContext(context.Background(), TemplateGroups)
+}
+
+// TemplateGroupsUpdateContext is like TemplateGroupsUpdate but uses ctx for the underlying requests.
+func (api *API) TemplateGroupsUpdateContext(ctx context.Context, TemplateGroups TemplateGroups) (err error) {
+	return NewRepository[TemplateGroup](api).Update(ctx, TemplateGroups)
 }
 
 // TemplateGroupsDelete Wrapper for templategroup.delete
 // Cleans GroupId in all TemplateGroups elements if call succeed.
 // https://www.zabbix.com/documentation/6.2/en/manual/api/reference/templategroup/delete
 func (api *API) TemplateGroupsDelete(TemplateGroups TemplateGroups) (err error) {
-	ids := make([]string, len(TemplateGroups))
-	for i, group := range TemplateGroups {
-		ids[i] = group.GroupID
-	}
+	return api.TemplateGroupsDeleteContext(context.Background(), TemplateGroups)
+}
 
-	err = api.TemplateGroupsDeleteByIds(ids)
-	if err == nil {
-		for i := range TemplateGroups {
-			TemplateGroups[i].GroupID = ""
-		}
-	}
-	return
+// TemplateGroupsDeleteContext is like TemplateGroupsDelete but uses ctx for the underlying requests.
+func (api *API) TemplateGroupsDeleteContext(ctx context.Context, TemplateGroups TemplateGroups) (err error) {
+	return NewRepository[TemplateGroup](api).Delete(ctx, TemplateGroups)
 }
 
 // TemplateGroupsDeleteByIds Wrapper for templategroup.delete
 // https://www.zabbix.com/documentation/6.2/en/manual/api/reference/templategroup/delete
 func (api *API) TemplateGroupsDeleteByIds(ids []string) (err error) {
-	response, err := api.CallWithError("templategroup.delete", ids)
-	if err != nil {
-		return
-	}
+	return api.TemplateGroupsDeleteByIdsContext(context.Background(), ids)
+}
 
-	result := response.Result.(map[string]interface{})
-	groupids := result["groupids"].([]interface{})
-	if len(ids) != len(groupids) {
-		err = &ExpectedMore{len(ids), len(groupids)}
-	}
-	return
+// TemplateGroupsDeleteByIdsContext is like TemplateGroupsDeleteByIds but uses ctx for the underlying requests.
+func (api *API) TemplateGroupsDeleteByIdsContext(ctx context.Context, ids []string) (err error) {
+	return NewRepository[TemplateGroup](api).DeleteByIDs(ctx, ids)
 }
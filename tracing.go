@@ -0,0 +1,203 @@
+package zabbix
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Span attributes set on the spans of JSON-RPC calls.
+const (
+	AttrRPCSystem    = "rpc.system"             // always "jsonrpc"
+	AttrRPCMethod    = "rpc.method"             // the JSON-RPC method, like "host.get"
+	AttrRequestID    = "rpc.jsonrpc.request_id" // the JSON-RPC request id, of the last attempt
+	AttrErrorCode    = "rpc.jsonrpc.error_code" // the code of the API error, if any
+	AttrErrorMessage = "rpc.jsonrpc.error_message"
+	AttrObjectCount  = "zabbix.object_count" // the number of objects or IDs sent, when params is an array
+)
+
+// Tracer starts a span for every JSON-RPC call, so the Zabbix calls show up in the traces of callers.
+// The span covers retries and session renewal, and wraps interceptors.
+// An OpenTelemetry tracer is adapted by starting spans with its Start method
+// and injecting headers with a propagation.TraceContext.
+type Tracer interface {
+	// Start starts a span named name as a child of the span of ctx, and returns a context holding it.
+	Start(ctx context.Context, name string) (context.Context, Span)
+	// Inject adds the propagation headers of the span of ctx, like the W3C traceparent, to header.
+	Inject(ctx context.Context, header http.Header)
+}
+
+// Span is one traced JSON-RPC call.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// spanKey holds the Span of the current call in contexts.
+type spanKey struct{}
+
+// startSpan starts the span of a call of method with params, if api has a Tracer.
+// The returned span is nil otherwise.
+func (api *API) startSpan(ctx context.Context, method string, params interface{}) (context.Context, Span) {
+	if api.Tracer == nil {
+		return ctx, nil
+	}
+	ctx, span := api.Tracer.Start(ctx, method)
+	span.SetAttribute(AttrRPCSystem, "jsonrpc")
+	span.SetAttribute(AttrRPCMethod, method)
+	if v := reflect.ValueOf(params); v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		span.SetAttribute(AttrObjectCount, v.Len())
+	}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// setRequestID sets the request id attribute on the span of ctx, if any.
+func setRequestID(ctx context.Context, id int32) {
+	if span, ok := ctx.Value(spanKey{}).(Span); ok {
+		span.SetAttribute(AttrRequestID, id)
+	}
+}
+
+// endSpan records err, or the API error of the response body, and ends span.
+func endSpan(span Span, body []byte, err error) {
+	if span == nil {
+		return
+	}
+	if err == nil && len(body) > 0 {
+		// openResult stops at the result, so successful responses are not decoded
+		_, err = openResult("", bytes.NewReader(body))
+		var e *Error
+		if !errors.As(err, &e) {
+			err = nil
+		}
+	}
+	if err != nil {
+		var e *Error
+		if errors.As(err, &e) {
+			span.SetAttribute(AttrErrorCode, e.Code)
+			span.SetAttribute(AttrErrorMessage, e.Message)
+		}
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// injectTrace adds the propagation headers of ctx to header, if api has a Tracer.
+func (api *API) injectTrace(ctx context.Context, header http.Header) {
+	if api.Tracer != nil {
+		api.Tracer.Inject(ctx, header)
+	}
+}
+
+// RecordingTracer records spans in memory, for tests.
+// It propagates the spans with W3C traceparent headers.
+type RecordingTracer struct {
+	mu    sync.Mutex
+	spans []*RecordedSpan
+}
+
+// RecordedSpan is a span recorded by RecordingTracer.
+type RecordedSpan struct {
+	Name       string
+	TraceID    string
+	SpanID     string
+	ParentID   string // empty for root spans
+	Attributes map[string]interface{}
+	Errors     []error
+	StartTime  time.Time
+	EndTime    time.Time // zero until the span ends
+
+	tracer *RecordingTracer
+}
+
+// Start Starts a span, child of the RecordedSpan of ctx if any.
+func (t *RecordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &RecordedSpan{
+		Name:       name,
+		SpanID:     randomHex(8),
+		Attributes: map[string]interface{}{},
+		StartTime:  time.Now(),
+		tracer:     t,
+	}
+	if parent, ok := ctx.Value(recordedSpanKey{}).(*RecordedSpan); ok {
+		span.TraceID = parent.TraceID
+		span.ParentID = parent.SpanID
+	} else {
+		span.TraceID = randomHex(16)
+	}
+
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+	return context.WithValue(ctx, recordedSpanKey{}, span), span
+}
+
+// Inject Sets the traceparent header of the RecordedSpan of ctx.
+func (t *RecordingTracer) Inject(ctx context.Context, header http.Header) {
+	if span, ok := ctx.Value(recordedSpanKey{}).(*RecordedSpan); ok {
+		header.Set("traceparent", span.Traceparent())
+	}
+}
+
+// Spans Returns copies of the recorded spans, in the order they started.
+func (t *RecordingTracer) Spans() []RecordedSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	spans := make([]RecordedSpan, len(t.spans))
+	for i, span := range t.spans {
+		spans[i] = *span
+		spans[i].Attributes = make(map[string]interface{}, len(span.Attributes))
+		for k, v := range span.Attributes {
+			spans[i].Attributes[k] = v
+		}
+		spans[i].Errors = append([]error(nil), span.Errors...)
+	}
+	return spans
+}
+
+// Reset Forgets the recorded spans.
+func (t *RecordingTracer) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans = nil
+}
+
+// recordedSpanKey holds the RecordedSpan of contexts.
+type recordedSpanKey struct{}
+
+// Traceparent Returns the W3C traceparent header value of the span.
+func (s *RecordedSpan) Traceparent() string {
+	return fmt.Sprintf("00-%s-%s-01", s.TraceID, s.SpanID)
+}
+
+func (s *RecordedSpan) SetAttribute(key string, value interface{}) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.Attributes[key] = value
+}
+
+func (s *RecordedSpan) RecordError(err error) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.Errors = append(s.Errors, err)
+}
+
+func (s *RecordedSpan) End() {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.EndTime = time.Now()
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,125 @@
+package zabbix_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	zapi "github.com/claranet/go-zabbix-api"
+)
+
+func TestTracing(t *testing.T) {
+	var traceparents []string
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		if testRPCMethod(r) == "host.delete" {
+			fmt.Fprint(w, `{"jsonrpc":"2.0","error":{"code":-32602,"message":"Invalid params.","data":"No permissions to referred object or it does not exist!"},"id":1}`)
+			return
+		}
+		fmt.Fprint(w, `{"jsonrpc":"2.0","result":[],"id":1}`)
+	})
+	tracer := &zapi.RecordingTracer{}
+	api.Tracer = tracer
+
+	ctx, parent := tracer.Start(context.Background(), "workflow")
+	if _, err := api.HostsGetContext(ctx, zapi.Params{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := api.HostsDeleteByIdsContext(ctx, []string{"1", "2"}); err == nil {
+		t.Fatal("expected an error")
+	}
+	parent.End()
+
+	spans := tracer.Spans()
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 3", len(spans))
+	}
+	workflow, get, del := spans[0], spans[1], spans[2]
+
+	if get.Name != "host.get" || get.TraceID != workflow.TraceID || get.ParentID != workflow.SpanID || get.EndTime.IsZero() {
+		t.Errorf("host.get span = %+v", get)
+	}
+	if get.Attributes[zapi.AttrRPCMethod] != "host.get" || get.Attributes[zapi.AttrRequestID] == nil || len(get.Errors) != 0 {
+		t.Errorf("host.get span attributes = %v, errors = %v", get.Attributes, get.Errors)
+	}
+	if traceparents[0] != get.Traceparent() {
+		t.Errorf("traceparent = %q, want %q", traceparents[0], get.Traceparent())
+	}
+
+	if del.Attributes[zapi.AttrErrorCode] != -32602 || del.Attributes[zapi.AttrObjectCount] != 2 || len(del.Errors) != 1 {
+		t.Errorf("host.delete span attributes = %v, errors = %v", del.Attributes, del.Errors)
+	}
+}
+
+func TestTracingBatch(t *testing.T) {
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		var calls []struct {
+			ID int32 `json:"id"`
+		}
+		testDecodeRequest(r, &calls)
+		fmt.Fprintf(w, `[{"jsonrpc":"2.0","result":[],"id":%d},{"jsonrpc":"2.0","error":{"code":-32602,"message":"Invalid params.","data":"Invalid parameter."},"id":%d}]`, calls[0].ID, calls[1].ID)
+	})
+	tracer := &zapi.RecordingTracer{}
+	api.Tracer = tracer
+
+	batch := api.NewBatch()
+	batch.Add("host.get", zapi.Params{}, nil)
+	batch.Add("item.get", zapi.Params{}, nil)
+	if err := batch.Do(); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := tracer.Spans()
+	if len(spans) != 2 || spans[0].Name != "host.get" || spans[1].Name != "item.get" {
+		t.Fatalf("spans = %+v", spans)
+	}
+	if len(spans[0].Errors) != 0 || spans[1].Attributes[zapi.AttrErrorCode] != -32602 {
+		t.Errorf("spans = %+v", spans)
+	}
+}
+
+func TestTracingBatchInterceptors(t *testing.T) {
+	var traceparent string
+	api := testFakeAPI(t, "6.0.0", func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		var calls []struct {
+			ID int32 `json:"id"`
+		}
+		testDecodeRequest(r, &calls)
+		fmt.Fprintf(w, `[{"jsonrpc":"2.0","result":[],"id":%d},{"jsonrpc":"2.0","result":[],"id":%d}]`, calls[0].ID, calls[1].ID)
+	})
+	tracer := &zapi.RecordingTracer{}
+	api.Tracer = tracer
+	api.Use(func(next zapi.CallFunc) zapi.CallFunc {
+		return func(ctx context.Context, method string, params interface{}) ([]byte, error) {
+			ctx, span := tracer.Start(ctx, "intercept "+method)
+			defer span.End()
+			return next(ctx, method, params)
+		}
+	})
+
+	ctx, parent := tracer.Start(context.Background(), "workflow")
+	batch := api.NewBatch()
+	batch.Add("host.get", zapi.Params{}, nil)
+	batch.Add("item.get", zapi.Params{}, nil)
+	if err := batch.DoContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	parent.End()
+
+	spans := map[string]zapi.RecordedSpan{}
+	for _, span := range tracer.Spans() {
+		spans[span.Name] = span
+	}
+	workflow := spans["workflow"]
+	for _, method := range []string{"host.get", "item.get"} {
+		call, intercept := spans[method], spans["intercept "+method]
+		if call.ParentID != workflow.SpanID || intercept.ParentID != call.SpanID {
+			t.Errorf("Expected the span of the interceptor to be a child of the %s span, got %+v and %+v", method, call, intercept)
+		}
+	}
+	if traceparent != workflow.Traceparent() {
+		t.Errorf("traceparent = %q, want %q", traceparent, workflow.Traceparent())
+	}
+}
@@ -0,0 +1,147 @@
+package zabbix
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"reflect"
+	"time"
+)
+
+// Trend is the hourly aggregate of the values of a Float or Unsigned item.
+// https://www.zabbix.com/documentation/current/manual/api/reference/trend/object
+type Trend struct {
+	ItemID string    `json:"itemid"`
+	Clock  time.Time `json:"clock"` // start of the hour
+	Num    int       `json:"num,string"`
+	Min    float64   `json:"value_min,string"`
+	Avg    float64   `json:"value_avg,string"`
+	Max    float64   `json:"value_max,string"`
+}
+
+// Trends is an array of Trend
+type Trends []Trend
+
+// UnmarshalJSON decodes the Unix time of the trend.
+func (t *Trend) UnmarshalJSON(b []byte) error {
+	type trend Trend
+	raw := struct {
+		*trend
+		Clock string `json:"clock"`
+	}{trend: (*trend)(t)}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	t.Clock = unixTime(raw.Clock, "")
+	return nil
+}
+
+// TrendSeries is the trends of an item aligned on hours, see Trends.Align.
+// Hours without trend have NaN values and a zero Num.
+type TrendSeries struct {
+	ItemID string
+	Num    []int
+	Min    []float64
+	Avg    []float64
+	Max    []float64
+}
+
+// Align Returns the hours from the one of from to the one of till, and the trends
+// of each of itemIDs for these hours, in the order of itemIDs.
+func (trends Trends) Align(itemIDs []string, from, till time.Time) (hours []time.Time, series []TrendSeries) {
+	start := from.Truncate(time.Hour)
+	for h := start; !h.After(till); h = h.Add(time.Hour) {
+		hours = append(hours, h)
+	}
+
+	index := make(map[string]int, len(itemIDs))
+	series = make([]TrendSeries, len(itemIDs))
+	for i, id := range itemIDs {
+		index[id] = i
+		s := TrendSeries{ItemID: id, Num: make([]int, len(hours))}
+		s.Min, s.Avg, s.Max = nanSlice(len(hours)), nanSlice(len(hours)), nanSlice(len(hours))
+		series[i] = s
+	}
+	for _, t := range trends {
+		i, ok := index[t.ItemID]
+		h := int(t.Clock.Sub(start) / time.Hour)
+		if !ok || t.Clock.Before(start) || h >= len(hours) {
+			continue
+		}
+		s := series[i]
+		s.Num[h], s.Min[h], s.Avg[h], s.Max[h] = t.Num, t.Min, t.Avg, t.Max
+	}
+	return
+}
+
+func nanSlice(n int) []float64 {
+	s := make([]float64, n)
+	for i := range s {
+		s[i] = math.NaN()
+	}
+	return s
+}
+
+// TrendsGet Wrapper for trend.get
+// The itemids are got in chunks of ChunkSize. The server limits each chunk,
+// so the merged results are sorted again by sortfield, if any, and cut to limit.
+// https://www.zabbix.com/documentation/current/manual/api/reference/trend/get
+func (api *API) TrendsGet(params Params) (res Trends, err error) {
+	return api.TrendsGetContext(context.Background(), params)
+}
+
+// TrendsGetContext is like TrendsGet but uses ctx for the HTTP requests.
+func (api *API) TrendsGetContext(ctx context.Context, params Params) (res Trends, err error) {
+	ids, chunked := params["itemids"].([]string)
+	n := 1
+	if chunked {
+		n = len(ids)
+	}
+	merge := chunked && api.chunked(n)
+	var order chunkOrder
+	if merge {
+		if order, err = newChunkOrder("trend", params, jsonFieldIndexes(reflect.TypeOf(Trend{}))); err != nil {
+			return
+		}
+	}
+	err = api.eachChunk(n, func(lo, hi int) error {
+		p := make(Params, len(params)+1)
+		for k, v := range params {
+			p[k] = v
+		}
+		if chunked {
+			p["itemids"] = ids[lo:hi]
+		}
+		if _, present := p["output"]; !present {
+			p["output"] = "extend"
+		}
+
+		var trends Trends
+		if err := api.CallWithErrorParseContext(ctx, "trend.get", p, &trends); err != nil {
+			return err
+		}
+		res = append(res, trends...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if merge {
+		res = mergeChunks(order, res)
+	}
+	return
+}
+
+// TrendsGetRange Gets the trends of items from the hour of from to the hour of till.
+func (api *API) TrendsGetRange(itemIDs []string, from, till time.Time) (res Trends, err error) {
+	return api.TrendsGetRangeContext(context.Background(), itemIDs, from, till)
+}
+
+// TrendsGetRangeContext is like TrendsGetRange but uses ctx for the HTTP request.
+func (api *API) TrendsGetRangeContext(ctx context.Context, itemIDs []string, from, till time.Time) (res Trends, err error) {
+	return api.TrendsGetContext(ctx, Params{
+		"itemids":   itemIDs,
+		"time_from": from.Truncate(time.Hour).Unix(),
+		"time_till": till.Unix(),
+	})
+}
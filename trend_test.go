@@ -0,0 +1,114 @@
+package zabbix_test
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	zapi "github.com/claranet/go-zabbix-api"
+)
+
+func TestTrendsGetRange(t *testing.T) {
+	var params map[string]interface{}
+	api := testFakeAPI(t, "7.0.0", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Params map[string]interface{} `json:"params"`
+		}
+		testDecodeRequest(r, &req)
+		params = req.Params
+		w.Write([]byte(`{"jsonrpc":"2.0","result":[
+			{"itemid":"1","clock":"1699999200","num":"60","value_min":"0.1","value_avg":"0.5","value_max":"0.9"},
+			{"itemid":"2","clock":"1700002800","num":"12","value_min":"3","value_avg":"4","value_max":"5"}
+		],"id":1}`))
+	})
+
+	from, till := time.Unix(1700000000, 0), time.Unix(1700003000, 0)
+	trends, err := api.TrendsGetRange([]string{"1", "2"}, from, till)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params["time_from"] != float64(1699999200) || params["time_till"] != float64(1700003000) || params["output"] != "extend" {
+		t.Errorf("Unexpected params %v", params)
+	}
+	if len(trends) != 2 || trends[0].Num != 60 || trends[0].Avg != 0.5 || !trends[0].Clock.Equal(time.Unix(1699999200, 0)) {
+		t.Errorf("Unexpected trends %#v", trends)
+	}
+
+	hours, series := trends.Align([]string{"2", "1", "3"}, from, till)
+	if len(hours) != 2 || !hours[0].Equal(time.Unix(1699999200, 0)) || !hours[1].Equal(time.Unix(1700002800, 0)) {
+		t.Fatalf("Unexpected hours %v", hours)
+	}
+	if len(series) != 3 || series[0].ItemID != "2" || series[1].ItemID != "1" {
+		t.Fatalf("Unexpected series %#v", series)
+	}
+	if !math.IsNaN(series[0].Avg[0]) || series[0].Avg[1] != 4 || series[0].Num[0] != 0 || series[0].Num[1] != 12 {
+		t.Errorf("Unexpected series of item 2 %#v", series[0])
+	}
+	if series[1].Max[0] != 0.9 || !math.IsNaN(series[1].Max[1]) {
+		t.Errorf("Unexpected series of item 1 %#v", series[1])
+	}
+	if !math.IsNaN(series[2].Min[0]) || !math.IsNaN(series[2].Min[1]) {
+		t.Errorf("Unexpected series of item 3 %#v", series[2])
+	}
+
+	if hours, series := (zapi.Trends{}).Align([]string{"1"}, till, from); len(hours) != 0 || len(series[0].Avg) != 0 {
+		t.Errorf("Expected no hours for an empty range, got %v %#v", hours, series)
+	}
+}
+
+func TestTrendsGetRangeChunked(t *testing.T) {
+	var requests []map[string]interface{}
+	api := testFakeAPI(t, "7.0.0", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Params map[string]interface{} `json:"params"`
+		}
+		testDecodeRequest(r, &req)
+		requests = append(requests, req.Params)
+		id := req.Params["itemids"].([]interface{})[0].(string)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","result":[
+			{"itemid":"%[1]s","clock":"1699999200","num":"60","value_min":"1","value_avg":"%[1]s","value_max":"9"},
+			{"itemid":"%[1]s","clock":"1700002800","num":"60","value_min":"1","value_avg":"%[1]s","value_max":"9"}
+		],"id":1}`, id)
+	})
+	api.ChunkSize = 1
+
+	from, till := time.Unix(1700000000, 0), time.Unix(1700003000, 0)
+	trends, err := api.TrendsGetRange([]string{"1", "2"}, from, till)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(requests))
+	}
+	for i, p := range requests {
+		if ids := p["itemids"].([]interface{}); len(ids) != 1 || ids[0] != fmt.Sprint(i+1) ||
+			p["time_from"] != float64(1699999200) || p["time_till"] != float64(1700003000) || p["output"] != "extend" {
+			t.Errorf("Unexpected params %v", p)
+		}
+	}
+
+	hours, series := trends.Align([]string{"1", "2"}, from, till)
+	if len(hours) != 2 || len(series) != 2 {
+		t.Fatalf("Unexpected hours %v and series %#v", hours, series)
+	}
+	for i, s := range series {
+		if want := float64(i + 1); s.Avg[0] != want || s.Avg[1] != want {
+			t.Errorf("Unexpected series %#v", s)
+		}
+	}
+
+	trends, err = api.TrendsGet(zapi.Params{"itemids": []string{"1", "2"}, "sortfield": "clock", "sortorder": "DESC", "limit": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, trend := range trends {
+		got = append(got, fmt.Sprint(trend.ItemID, " ", trend.Clock.Unix()))
+	}
+	if want := []string{"1 1700002800", "2 1700002800", "1 1699999200"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected the merged trends sorted and limited, got %v", got)
+	}
+}
@@ -1,5 +1,7 @@
 package zabbix
 
+import "context"
+
 type (
 	// SeverityType of a trigger
 	// Zabbix severity see : https://www.zabbix.com/documentation/3.2/manual/api/reference/trigger/object
@@ -82,101 +84,86 @@ type TriggerIDs []TriggerID
 // TriggersGet Wrapper for trigger.get
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/trigger/get
 func (api *API) TriggersGet(params Params) (res Triggers, err error) {
-	if _, present := params["output"]; !present {
-		params["output"] = "extend"
-	}
-	err = api.CallWithErrorParse("trigger.get", params, &res)
-	return
+	return api.TriggersGetContext(context.Background(), params)
+}
+
+// TriggersGetContext is like TriggersGet but uses ctx for the underlying requests.
+func (api *API) TriggersGetContext(ctx context.Context, params Params) (res Triggers, err error) {
+	return NewRepository[Trigger](api).Get(ctx, params)
+}
+
+// TriggersIter Returns a Stream of the triggers matching params, decoded one at a time.
+// Use it instead of TriggersGet for results too large to hold in memory.
+func (api *API) TriggersIter(ctx context.Context, params Params) *Stream[Trigger] {
+	return NewRepository[Trigger](api).Iter(ctx, params)
 }
 
 // TriggerGetByID Gets trigger by Id only if there is exactly 1 matching host.
 func (api *API) TriggerGetByID(id string) (res *Trigger, err error) {
-	triggers, err := api.TriggersGet(Params{"triggerids": id})
-	if err != nil {
-		return
-	}
+	return api.TriggerGetByIDContext(context.Background(), id)
+}
 
-	if len(triggers) != 1 {
-		e := ExpectedOneResult(len(triggers))
-		err = &e
-		return
-	}
-	res = &triggers[0]
-	return
+// TriggerGetByIDContext is like TriggerGetByID but uses ctx for the underlying requests.
+func (api *API) TriggerGetByIDContext(ctx context.Context, id string) (res *Trigger, err error) {
+	return NewRepository[Trigger](api).GetByID(ctx, id)
 }
 
 // TriggersCreate Wrapper for trigger.create
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/trigger/create
 func (api *API) TriggersCreate(triggers Triggers) (err error) {
-	response, err := api.CallWithError("trigger.create", triggers)
-	if err != nil {
-		return
-	}
+	return api.TriggersCreateContext(context.Background(), triggers)
+}
 
-	result := response.Result.(map[string]interface{})
-	triggerids := result["triggerids"].([]interface{})
-	for i, id := range triggerids {
-		triggers[i].TriggerID = id.(string)
-	}
-	return
+// TriggersCreateContext is like TriggersCreate but uses ctx for the underlying requests.
+func (api *API) TriggersCreateContext(ctx context.Context, triggers Triggers) (err error) {
+	return NewRepository[Trigger](api).Create(ctx, triggers)
 }
 
 // TriggersUpdate Wrapper for trigger.update
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/trigger/update
 func (api *API) TriggersUpdate(triggers Triggers) (err error) {
-	_, err = api.CallWithError("trigger.update", triggers)
-	return
+	return api.TriggersUpdateContext(context.Background(), triggers)
+}
+
+// TriggersUpdateContext is like TriggersUpdate but uses ctx for the underlying requests.
+func (api *API) TriggersUpdateContext(ctx context.Context, triggers Triggers) (err error) {
+	return NewRepository[Trigger](api).Update(ctx, triggers)
 }
 
 // TriggersDelete Wrapper for trigger.delete
 // Cleans ItemId in all triggers elements if call succeed.
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/trigger/delete
 func (api *API) TriggersDelete(triggers Triggers) (err error) {
-	ids := make([]string, len(triggers))
-	for i, trigger := range triggers {
-		ids[i] = trigger.TriggerID
-	}
+	return api.TriggersDeleteContext(context.Background(), triggers)
+}
 
-	err = api.TriggersDeleteByIds(ids)
-	if err == nil {
-		for i := range triggers {
-			triggers[i].TriggerID = ""
-		}
-	}
-	return
+// TriggersDeleteContext is like TriggersDelete but uses ctx for the underlying requests.
+func (api *API) TriggersDeleteContext(ctx context.Context, triggers Triggers) (err error) {
+	return NewRepository[Trigger](api).Delete(ctx, triggers)
 }
 
 // TriggersDeleteByIds Wrapper for trigger.delete
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/trigger/delete
 func (api *API) TriggersDeleteByIds(ids []string) (err error) {
-	deleteIds, err := api.TriggersDeleteIDs(ids)
-	if err != nil {
-		return
-	}
-	l := len(deleteIds)
-	if len(ids) != l {
-		err = &ExpectedMore{len(ids), l}
-	}
-	return
+	return api.TriggersDeleteByIdsContext(context.Background(), ids)
+}
+
+// TriggersDeleteByIdsContext is like TriggersDeleteByIds but uses ctx for the underlying requests.
+func (api *API) TriggersDeleteByIdsContext(ctx context.Context, ids []string) (err error) {
+	return NewRepository[Trigger](api).DeleteByIDs(ctx, ids)
 }
 
 // TriggersDeleteIDs Wrapper for trigger.delete
 // return the id of the deleted trigger
 func (api *API) TriggersDeleteIDs(ids []string) (triggerids []interface{}, err error) {
-	response, err := api.CallWithError("trigger.delete", ids)
-	if err != nil {
-		return
-	}
+	return api.TriggersDeleteIDsContext(context.Background(), ids)
+}
 
-	result := response.Result.(map[string]interface{})
-	triggerids1, ok := result["triggerids"].([]interface{})
-	if !ok {
-		triggerids2 := result["triggerids"].(map[string]interface{})
-		for _, id := range triggerids2 {
-			triggerids = append(triggerids, id)
-		}
-	} else {
-		triggerids = triggerids1
+// TriggersDeleteIDsContext is like TriggersDeleteIDs but uses ctx for the underlying requests.
+func (api *API) TriggersDeleteIDsContext(ctx context.Context, ids []string) (triggerids []interface{}, err error) {
+	deleted, err := NewRepository[Trigger](api).deleteIDs(ctx, ids)
+	for _, id := range deleted {
+		triggerids = append(triggerids, id)
 	}
 	return
 }
@@ -1,5 +1,7 @@
 package zabbix
 
+import "context"
+
 // TriggerPrototype represent Zabbix trigger prototype object
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/triggerprototype/object
 type TriggerPrototype struct {
@@ -36,102 +38,81 @@ type TriggerPrototypeIDs []TriggerPrototypeID
 // TriggerPrototypesGet Wrapper for trigger.get
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/triggerprototype/get
 func (api *API) TriggerPrototypesGet(params Params) (res TriggerPrototypes, err error) {
-	if _, present := params["output"]; !present {
-		params["output"] = "extend"
-	}
-	err = api.CallWithErrorParse("triggerprototype.get", params, &res)
-	return
+	return api.TriggerPrototypesGetContext(context.Background(), params)
+}
+
+// TriggerPrototypesGetContext is like TriggerPrototypesGet but uses ctx for the underlying requests.
+func (api *API) TriggerPrototypesGetContext(ctx context.Context, params Params) (res TriggerPrototypes, err error) {
+	return NewRepository[TriggerPrototype](api).Get(ctx, params)
 }
 
 // TriggerPrototypeGetByID Gets trigger by Id only if there is exactly 1 matching trigger.
 func (api *API) TriggerPrototypeGetByID(id string) (res *TriggerPrototype, err error) {
-	triggers, err := api.TriggerPrototypesGet(Params{"triggerids": id})
-	if err != nil {
-		return
-	}
+	return api.TriggerPrototypeGetByIDContext(context.Background(), id)
+}
 
-	if len(triggers) != 1 {
-		e := ExpectedOneResult(len(triggers))
-		err = &e
-		return
-	}
-	res = &triggers[0]
-	return
+// TriggerPrototypeGetByIDContext is like TriggerPrototypeGetByID but uses ctx for the underlying requests.
+func (api *API) TriggerPrototypeGetByIDContext(ctx context.Context, id string) (res *TriggerPrototype, err error) {
+	return NewRepository[TriggerPrototype](api).GetByID(ctx, id)
 }
 
 // TriggerPrototypesCreate Wrapper for trigger.create
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/triggerprototype/create
 func (api *API) TriggerPrototypesCreate(triggers TriggerPrototypes) (err error) {
-	response, err := api.CallWithError("triggerprototype.create", triggers)
-	if err != nil {
-		return
-	}
+	return api.TriggerPrototypesCreateContext(context.Background(), triggers)
+}
 
-	result := response.Result.(map[string]interface{})
-	triggerids := result["triggerids"].([]interface{})
-	for i, id := range triggerids {
-		triggers[i].TriggerID = id.(string)
-	}
-	return
+// TriggerPrototypesCreateContext is like TriggerPrototypesCreate but uses ctx for the underlying requests.
+func (api *API) TriggerPrototypesCreateContext(ctx context.Context, triggers TriggerPrototypes) (err error) {
+	return NewRepository[TriggerPrototype](api).Create(ctx, triggers)
 }
 
 // TriggerPrototypesUpdate Wrapper for trigger.update
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/triggerprototype/update
 func (api *API) TriggerPrototypesUpdate(triggers TriggerPrototypes) (err error) {
-	_, err = api.CallWithError("triggerprototype.update", triggers)
-	return
+	return api.TriggerPrototypesUpdateContext(context.Background(), triggers)
+}
+
+// TriggerPrototypesUpdateContext is like TriggerPrototypesUpdate but uses ctx for the underlying requests.
+func (api *API) TriggerPrototypesUpdateContext(ctx context.Context, triggers TriggerPrototypes) (err error) {
+	return NewRepository[TriggerPrototype](api).Update(ctx, triggers)
 }
 
 // TriggerPrototypesDelete Wrapper for trigger.delete
 // Cleans TriggerID in all triggers elements if call succeed.
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/triggerprototype/delete
 func (api *API) TriggerPrototypesDelete(triggers TriggerPrototypes) (err error) {
-	ids := make([]string, len(triggers))
-	for i, trigger := range triggers {
-		ids[i] = trigger.TriggerID
-	}
+	return api.TriggerPrototypesDeleteContext(context.Background(), triggers)
+}
 
-	err = api.TriggerPrototypesDeleteByIds(ids)
-	if err == nil {
-		for i := range triggers {
-			triggers[i].TriggerID = ""
-		}
-	}
-	return
+// TriggerPrototypesDeleteContext is like TriggerPrototypesDelete but uses ctx for the underlying requests.
+func (api *API) TriggerPrototypesDeleteContext(ctx context.Context, triggers TriggerPrototypes) (err error) {
+	return NewRepository[TriggerPrototype](api).Delete(ctx, triggers)
 }
 
 // TriggerPrototypesDeleteByIds Wrapper for trigger.delete
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/triggerprototype/delete
 func (api *API) TriggerPrototypesDeleteByIds(ids []string) (err error) {
-	triggerids1, err := api.TriggerPrototypesDeleteIDs((ids))
-	if err != nil {
-		return
-	}
+	return api.TriggerPrototypesDeleteByIdsContext(context.Background(), ids)
+}
 
-	if len(triggerids1) != len(ids) {
-		err = &ExpectedMore{len(ids), len(triggerids1)}
-	}
-	return
+// TriggerPrototypesDeleteByIdsContext is like TriggerPrototypesDeleteByIds but uses ctx for the underlying requests.
+func (api *API) TriggerPrototypesDeleteByIdsContext(ctx context.Context, ids []string) (err error) {
+	return NewRepository[TriggerPrototype](api).DeleteByIDs(ctx, ids)
 }
 
 // TriggerPrototypesDeleteIDs Wrapper for trigger.delete
 // return the id of the deleted trigger prototype
 // https://www.zabbix.com/documentation/3.2/manual/api/reference/triggerprototype/delete
 func (api *API) TriggerPrototypesDeleteIDs(ids []string) (triggerids []interface{}, err error) {
-	response, err := api.CallWithError("triggerprototype.delete", ids)
-	if err != nil {
-		return
-	}
+	return api.TriggerPrototypesDeleteIDsContext(context.Background(), ids)
+}
 
-	result := response.Result.(map[string]interface{})
-	triggerids1, ok := result["triggerids"].([]interface{})
-	if !ok {
-		triggerids2 := result["triggerids"].(map[string]interface{})
-		for _, id := range triggerids2 {
-			triggerids = append(triggerids, id)
-		}
-	} else {
-		triggerids = triggerids1
+// TriggerPrototypesDeleteIDsContext is like TriggerPrototypesDeleteIDs but uses ctx for the underlying requests.
+func (api *API) TriggerPrototypesDeleteIDsContext(ctx context.Context, ids []string) (triggerids []interface{}, err error) {
+	deleted, err := NewRepository[TriggerPrototype](api).deleteIDs(ctx, ids)
+	for _, id := range deleted {
+		triggerids = append(triggerids, id)
 	}
 	return
 }
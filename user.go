@@ -1,5 +1,7 @@
 package zabbix
 
+import "context"
+
 type (
 	// Whether to pause escalation during maintenance periods or not.
 	// "debug_mode" in https://www.zabbix.com/documentation/4.0/manual/api/reference/user/object#user_group
@@ -49,7 +51,8 @@ type User struct {
 	Medias          Medias     `json:"medias,omitempty"`
 	UsrGrps         UserGroups `json:"usrgrps,omitempty"`
 
-	// Legacy field for backward compatibility
+	// Legacy fields of Zabbix 5.0, Username and RoleID are sent as these to older servers
+	// and filled from them, see FeatureUsername and FeatureRoles.
 	Alias string   `json:"alias,omitempty"`
 	Type  UserType `json:"type,string,omitempty"`
 }
@@ -76,47 +79,43 @@ type Medias []Media
 // UsersGet Wrapper for user.get
 // https://www.zabbix.com/documentation/4.0/manual/api/reference/user/get
 func (api *API) UsersGet(params Params) (res Users, err error) {
-	if _, present := params["output"]; !present {
-		params["output"] = "extend"
-	}
-	err = api.CallWithErrorParse("user.get", params, &res)
-	return
+	return api.UsersGetContext(context.Background(), params)
+}
+
+// UsersGetContext is like UsersGet but uses ctx for the underlying requests.
+func (api *API) UsersGetContext(ctx context.Context, params Params) (res Users, err error) {
+	return NewRepository[User](api).Get(ctx, params)
 }
 
 // UserCreate Wrapper for user.create
 // https://www.zabbix.com/documentation/current/manual/api/reference/user/create
 func (api *API) UsersCreate(users Users) (err error) {
-	response, err := api.CallWithError("user.create", users)
-	if err != nil {
-		return
-	}
-
-	result := response.Result.(map[string]interface{})
-	userids := result["userids"].([]interface{})
-	for i, userid := range userids {
-		users[i].UserID = userid.(string)
-	}
-	return
+	return api.UsersCreateContext(context.Background(), users)
+}
+
+// UsersCreateContext is like UsersCreate but uses ctx for the underlying requests.
+func (api *API) UsersCreateContext(ctx context.Context, users Users) (err error) {
+	return NewRepository[User](api).Create(ctx, users)
 }
 
 // UserUpdate Wrapper for user.update
 // https://www.zabbix.com/documentation/current/manual/api/reference/user/update
 func (api *API) UsersUpdate(users Users) (err error) {
-	_, err = api.CallWithError("user.update", users)
-	return
+	return api.UsersUpdateContext(context.Background(), users)
+}
+
+// UsersUpdateContext is like UsersUpdate but uses ctx for the underlying requests.
+func (api *API) UsersUpdateContext(ctx context.Context, users Users) (err error) {
+	return NewRepository[User](api).Update(ctx, users)
 }
 
 // UserDelete Wrapper for user.delete
 // https://www.zabbix.com/documentation/current/manual/api/reference/user/delete
 func (api *API) UsersDeleteByIds(userids []string) (err error) {
-	response, err := api.CallWithError("user.delete", userids)
-	if err != nil {
-		return
-	}
-
-	result := response.Result.(map[string]interface{})
-	if deletedids, ok := result["userids"].([]interface{}); ok && len(deletedids) != len(userids) {
-		err = &ExpectedMore{len(userids), len(deletedids)}
-	}
-	return
+	return api.UsersDeleteByIdsContext(context.Background(), userids)
+}
+
+// UsersDeleteByIdsContext is like UsersDeleteByIds but uses ctx for the underlying requests.
+func (api *API) UsersDeleteByIdsContext(ctx context.Context, userids []string) (err error) {
+	return NewRepository[User](api).DeleteByIDs(ctx, userids)
 }
@@ -1,5 +1,7 @@
 package zabbix
 
+import "context"
+
 type (
 	// Whether to pause escalation during maintenance periods or not.
 	// "debug_mode" in https://www.zabbix.com/documentation/4.0/manual/api/reference/usergroup/object#user_group
@@ -27,9 +29,10 @@ type UserGroups []UserGroup
 // UserGroupsGet Wrapper for usergroup.get
 // https://www.zabbix.com/documentation/4.0/manual/api/reference/usergroup/get
 func (api *API) UserGroupsGet(params Params) (res UserGroups, err error) {
-	if _, present := params["output"]; !present {
-		params["output"] = "extend"
-	}
-	err = api.CallWithErrorParse("usergroup.get", params, &res)
-	return
+	return api.UserGroupsGetContext(context.Background(), params)
+}
+
+// UserGroupsGetContext is like UserGroupsGet but uses ctx for the underlying requests.
+func (api *API) UserGroupsGetContext(ctx context.Context, params Params) (res UserGroups, err error) {
+	return NewRepository[UserGroup](api).Get(ctx, params)
 }
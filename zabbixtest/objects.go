@@ -0,0 +1,1470 @@
+package zabbixtest
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// kind describes the API of one object type, like host for host.get and host.create.
+type kind struct {
+	name      string // API name, like "host"
+	id        string // ID field, like "hostid"
+	seq       string // sequence of the IDs, shared by the objects stored in one Zabbix table
+	idsParam  string // get parameter filtering by IDs, defaults to id+"s"
+	resultKey string // key of the IDs returned by create and update, defaults to idsParam
+	deleteKey string // key of the IDs returned by delete, defaults to resultKey
+	since     string // first version with the API, empty for all
+	until     string // first version without the API, empty for all
+
+	required []string // fields required by create
+	defaults object   // fields set by create when missing
+	hidden   []string // fields returned only by select* parameters, or never
+	readonly []string // fields rejected by update
+
+	links   map[string]link      // get parameters filtering by related objects, like groupids
+	selects map[string]selection // select* parameters
+
+	prepare  func(s *Server, o, old object, path string) error // validates o before it is stored, old is nil on create
+	conflict func(s *Server, o object) error                   // reports objects o would duplicate
+	saved    func(s *Server, o object, path string) error      // writes the objects depending on o, once stored
+	deleting func(s *Server, ids map[string]bool) error        // reports why objects cannot be deleted
+	deleted  func(s *Server, o object)                         // removes the objects depending on a deleted one
+}
+
+// link tells whether o is related to one of ids.
+type link func(s *Server, o object, ids map[string]bool) bool
+
+// selection is a select* parameter, joining objects to the results.
+type selection struct {
+	key     string // result key of the joined objects
+	one     bool   // the join is one object instead of an array
+	objects func(s *Server, o object) []object
+}
+
+// kinds are the APIs of the server, by name.
+var kinds map[string]*kind
+
+// firstIDs are the last IDs of the sequences in new servers, the IDs of seeded objects excepted.
+var firstIDs = map[string]int{
+	"groupid":       100,
+	"hostid":        10500,
+	"interfaceid":   100,
+	"hostmacroid":   100,
+	"itemid":        40000,
+	"triggerid":     20000,
+	"functionid":    30000,
+	"applicationid": 1000,
+	"actionid":      10,
+	"conditionid":   10,
+	"operationid":   10,
+	"mediatypeid":   100,
+	"mediaid":       10,
+	"roleid":        100,
+	"userid":        100,
+	"usrgrpid":      100,
+}
+
+func init() {
+	kinds = map[string]*kind{}
+	for _, k := range []*kind{
+		hostGroupKind(), templateGroupKind(), hostKind(), templateKind(), macroKind(),
+		itemKind("item", "Item"), itemKind("discoveryrule", "LLD rule"), itemKind("itemprototype", "Item prototype"),
+		triggerKind("trigger", "Trigger"), triggerKind("triggerprototype", "Trigger prototype"),
+		applicationKind(), actionKind(), mediaTypeKind(), roleKind(), userKind(), userGroupKind(),
+	} {
+		if k.seq == "" {
+			k.seq = k.id
+		}
+		if k.idsParam == "" {
+			k.idsParam = k.id + "s"
+		}
+		if k.resultKey == "" {
+			k.resultKey = k.idsParam
+		}
+		if k.deleteKey == "" {
+			k.deleteKey = k.resultKey
+		}
+		kinds[k.name] = k
+	}
+}
+
+func hostGroupKind() *kind {
+	return &kind{
+		name:     "hostgroup",
+		id:       "groupid",
+		required: []string{"name"},
+		defaults: object{"internal": "0", "flags": "0"},
+		links: map[string]link{
+			"hostids":     referredBy("host", "groups", "groupid", "groupid"),
+			"templateids": referredBy("template", "groups", "groupid", "groupid"),
+		},
+		selects: map[string]selection{
+			"selectHosts":     {key: "hosts", objects: referring("host", "groups", "groupid", "groupid")},
+			"selectTemplates": {key: "templates", objects: referring("template", "groups", "groupid", "groupid")},
+		},
+		conflict: unique("hostgroup", `Host group "%s" already exists.`, "name"),
+		deleting: func(s *Server, ids map[string]bool) error {
+			if err := s.mustKeep("host", "groups", "groupid", ids, `Host "%s" cannot be without host group.`); err != nil {
+				return err
+			}
+			return s.mustKeep("template", "groups", "groupid", ids, `Template "%s" cannot be without host group.`)
+		},
+		deleted: func(s *Server, o object) {
+			s.unref("host", "groups", "groupid", str(o["groupid"]))
+			s.unref("template", "groups", "groupid", str(o["groupid"]))
+		},
+	}
+}
+
+func templateGroupKind() *kind {
+	return &kind{
+		name:     "templategroup",
+		id:       "groupid",
+		since:    "6.2",
+		required: []string{"name"},
+		defaults: object{"uuid": ""},
+		links: map[string]link{
+			"templateids": referredBy("template", "groups", "groupid", "groupid"),
+		},
+		selects: map[string]selection{
+			"selectTemplates": {key: "templates", objects: referring("template", "groups", "groupid", "groupid")},
+		},
+		conflict: unique("templategroup", `Template group "%s" already exists.`, "name"),
+		deleting: func(s *Server, ids map[string]bool) error {
+			return s.mustKeep("template", "groups", "groupid", ids, `Template "%s" cannot be without template group.`)
+		},
+		deleted: func(s *Server, o object) {
+			s.unref("template", "groups", "groupid", str(o["groupid"]))
+		},
+	}
+}
+
+func hostKind() *kind {
+	return &kind{
+		name:     "host",
+		id:       "hostid",
+		required: []string{"host", "groups"},
+		defaults: object{"name": "", "status": "0", "description": "", "flags": "0", "inventory_mode": "-1", "proxy_hostid": "0"},
+		hidden:   []string{"groups", "templates", "interfaces", "tags", "inventory"},
+		links: map[string]link{
+			"groupids":     refersTo("groups", "groupid"),
+			"templateids":  refersTo("templates", "templateid"),
+			"interfaceids": refersTo("interfaces", "interfaceid"),
+			"itemids": func(s *Server, o object, ids map[string]bool) bool {
+				return len(s.where("item", func(i object) bool { return ids[str(i["itemid"])] && i["hostid"] == o["hostid"] })) > 0
+			},
+		},
+		selects: map[string]selection{
+			"selectGroups":          {key: "groups", objects: joined("hostgroup", "groups", "groupid")},
+			"selectHostGroups":      {key: "hostgroups", objects: joined("hostgroup", "groups", "groupid")},
+			"selectParentTemplates": {key: "parentTemplates", objects: joined("template", "templates", "templateid")},
+			"selectInterfaces":      {key: "interfaces", objects: stored("interfaces")},
+			"selectMacros":          {key: "macros", objects: owned("usermacro", "hostid", "hostid")},
+			"selectItems":           {key: "items", objects: owned("item", "hostid", "hostid")},
+			"selectDiscoveries":     {key: "discoveries", objects: owned("discoveryrule", "hostid", "hostid")},
+			"selectTriggers":        {key: "triggers", objects: hostTriggers("trigger", "hostid")},
+			"selectTags":            {key: "tags", objects: stored("tags")},
+			"selectInventory":       {key: "inventory", one: true, objects: stored("inventory")},
+		},
+		prepare: func(s *Server, o, old object, path string) error {
+			if err := s.prepareHost(o, path, "hostgroup"); err != nil {
+				return err
+			}
+			return s.prepareInterfaces(o, old, path)
+		},
+		conflict: hostConflict("hostid"),
+		saved:    saveMacros("hostid"),
+		deleted:  deleteHost("hostid"),
+	}
+}
+
+func templateKind() *kind {
+	return &kind{
+		name:     "template",
+		id:       "templateid",
+		seq:      "hostid",
+		required: []string{"host", "groups"},
+		defaults: object{"name": "", "description": "", "uuid": ""},
+		hidden:   []string{"groups", "templates", "tags", "hosts"},
+		links: map[string]link{
+			"groupids":          refersTo("groups", "groupid"),
+			"parentTemplateids": refersTo("templates", "templateid"),
+			"hostids":           referredBy("host", "templates", "templateid", "templateid"),
+		},
+		selects: map[string]selection{
+			"selectGroups": {key: "groups", objects: func(s *Server, o object) []object {
+				return joined(s.templateGroups(), "groups", "groupid")(s, o)
+			}},
+			"selectTemplateGroups":  {key: "templategroups", objects: joined("templategroup", "groups", "groupid")},
+			"selectParentTemplates": {key: "parentTemplates", objects: joined("template", "templates", "templateid")},
+			"selectTemplates":       {key: "templates", objects: referring("template", "templates", "templateid", "templateid")},
+			"selectHosts":           {key: "hosts", objects: referring("host", "templates", "templateid", "templateid")},
+			"selectMacros":          {key: "macros", objects: owned("usermacro", "hostid", "templateid")},
+			"selectItems":           {key: "items", objects: owned("item", "hostid", "templateid")},
+			"selectDiscoveries":     {key: "discoveries", objects: owned("discoveryrule", "hostid", "templateid")},
+			"selectTriggers":        {key: "triggers", objects: hostTriggers("trigger", "templateid")},
+			"selectTags":            {key: "tags", objects: stored("tags")},
+		},
+		prepare: func(s *Server, o, old object, path string) error {
+			delete(o, "hosts")
+			return s.prepareHost(o, path, s.templateGroups())
+		},
+		conflict: hostConflict("templateid"),
+		saved:    saveMacros("templateid"),
+		deleted:  deleteHost("templateid"),
+	}
+}
+
+// templateGroups returns the API of the groups of templates, host groups before Zabbix 6.2.
+func (s *Server) templateGroups() string {
+	if s.atLeast("6.2") {
+		return "templategroup"
+	}
+	return "hostgroup"
+}
+
+// prepareHost validates the names, groups and linked templates of a host or template.
+func (s *Server) prepareHost(o object, path, groups string) error {
+	if str(o["host"]) == "" {
+		return errInvalidParams(`Invalid parameter "%s/host": cannot be empty.`, path)
+	}
+	if str(o["name"]) == "" {
+		o["name"] = o["host"]
+	}
+	if len(list(o["groups"])) == 0 {
+		return errInvalidParams(`Invalid parameter "%s/groups": cannot be empty.`, path)
+	}
+	if err := s.checkRefs(o, "groups", "groupid", groups); err != nil {
+		return err
+	}
+
+	if err := s.checkRefs(o, "templates", "templateid", "template"); err != nil {
+		return err
+	}
+	if clear, ok := idSet(o["templates_clear"]); ok {
+		var templates []interface{}
+		for _, id := range refs(o, "templates", "templateid") {
+			if !clear[id] {
+				templates = append(templates, object{"templateid": id})
+			}
+		}
+		o["templates"] = templates
+		delete(o, "templates_clear")
+	}
+	return nil
+}
+
+// prepareInterfaces gives IDs to the interfaces of a host, keeping those of its existing interfaces,
+// and checks it has one main interface per type.
+func (s *Server) prepareInterfaces(o, old object, path string) error {
+	interfaces, ok := o["interfaces"].([]interface{})
+	if !ok {
+		return nil
+	}
+	existing := map[string]bool{}
+	if old != nil {
+		for _, id := range refs(old, "interfaces", "interfaceid") {
+			existing[id] = true
+		}
+	}
+
+	mains := map[string]int{}
+	for i, v := range interfaces {
+		iface, ok := v.(object)
+		if !ok {
+			return errInvalidParams(`Invalid parameter "%s/interfaces/%d": an array is expected.`, path, i+1)
+		}
+		if !existing[str(iface["interfaceid"])] {
+			iface["interfaceid"] = s.nextID("interfaceid")
+		}
+		iface["hostid"] = o["hostid"]
+		setDefaults(iface, object{"main": "0", "type": "1", "useip": "1", "ip": "", "dns": "", "port": "10050"})
+		if iface["main"] == "1" {
+			mains[str(iface["type"])]++
+		} else {
+			mains[str(iface["type"])] += 0
+		}
+	}
+	names := map[string]string{"1": "Agent", "2": "SNMP", "3": "IPMI", "4": "JMX"}
+	for t, n := range mains {
+		switch {
+		case n > 1:
+			return errInvalidParams(`Host "%s" cannot have more than one default interface of the same type.`, str(o["host"]))
+		case n == 0:
+			return errInvalidParams(`No default interface for "%s" type on "%s".`, names[t], str(o["host"]))
+		}
+	}
+	return nil
+}
+
+// hostConflict reports hosts and templates with the host or visible name of a host or template.
+func hostConflict(idField string) func(s *Server, o object) error {
+	return func(s *Server, o object) error {
+		for _, name := range []string{"host", "template"} {
+			label := map[string]string{"host": "Host", "template": "Template"}[name]
+			for _, other := range s.rows(name) {
+				if str(other[kinds[name].id]) == str(o[idField]) {
+					continue
+				}
+				if other["host"] == o["host"] {
+					return errInvalidParams(`%s with the same name "%s" already exists.`, label, str(o["host"]))
+				}
+				if other["name"] == o["name"] {
+					return errInvalidParams(`%s with the same visible name "%s" already exists.`, label, str(o["name"]))
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// saveMacros replaces the user macros of a host or template by its "macros" field, if set.
+func saveMacros(idField string) func(s *Server, o object, path string) error {
+	return func(s *Server, o object, path string) error {
+		macros, ok := o["macros"]
+		delete(o, "macros")
+		if !ok {
+			return nil
+		}
+		id := str(o[idField])
+		for _, m := range s.where("usermacro", func(m object) bool { return str(m["hostid"]) == id }) {
+			s.remove("usermacro", str(m["hostmacroid"]))
+		}
+		k := kinds["usermacro"]
+		for i, v := range list(macros) {
+			m, ok := v.(object)
+			if !ok {
+				return errInvalidParams(`Invalid parameter "%s/macros/%d": an array is expected.`, path, i+1)
+			}
+			m = object{"macro": m["macro"], "value": m["value"], "type": m["type"], "description": m["description"]}
+			for field, v := range k.defaults {
+				if m[field] == nil {
+					m[field] = v
+				}
+			}
+			m["hostmacroid"] = s.nextID(k.seq)
+			m["hostid"] = id
+			if err := s.write(k, m, nil, path+"/macros/"+strconv.Itoa(i+1)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// deleteHost removes the objects of a deleted host or template, and the links to it.
+func deleteHost(idField string) func(s *Server, o object) {
+	return func(s *Server, o object) {
+		id := str(o[idField])
+		for _, name := range []string{"item", "discoveryrule", "usermacro", "application"} {
+			for _, child := range s.where(name, func(c object) bool { return str(c["hostid"]) == id }) {
+				s.remove(name, str(child[kinds[name].id]))
+			}
+		}
+		s.unref("host", "templates", "templateid", id)
+		s.unref("template", "templates", "templateid", id)
+	}
+}
+
+func macroKind() *kind {
+	return &kind{
+		name:      "usermacro",
+		id:        "hostmacroid",
+		required:  []string{"hostid", "macro"},
+		defaults:  object{"value": "", "type": "0", "description": ""},
+		resultKey: "hostmacroids",
+		links: map[string]link{
+			"hostids":     fieldIn("hostid"),
+			"templateids": fieldIn("hostid"),
+			"groupids":    hostInGroups("hostid"),
+			"globalmacro": func(*Server, object, map[string]bool) bool { return false },
+		},
+		selects: map[string]selection{
+			"selectHosts":     {key: "hosts", objects: byField("host", "hostid")},
+			"selectTemplates": {key: "templates", objects: byField("template", "hostid")},
+		},
+		prepare: func(s *Server, o, old object, path string) error {
+			if old != nil {
+				o["hostid"] = old["hostid"]
+			}
+			if host, _ := s.hostOrTemplate(str(o["hostid"])); host == nil {
+				return errNoObject()
+			}
+			macro := str(o["macro"])
+			if !macroPattern.MatchString(macro) {
+				return errInvalidParams(`Invalid parameter "%s/macro": incorrect syntax near "%s".`, path, strings.TrimPrefix(macro, "{$"))
+			}
+			return nil
+		},
+		conflict: func(s *Server, o object) error {
+			for _, other := range s.rows("usermacro") {
+				if other["hostid"] == o["hostid"] && other["macro"] == o["macro"] && other["hostmacroid"] != o["hostmacroid"] {
+					host, _ := s.hostOrTemplate(str(o["hostid"]))
+					return errInvalidParams(`Macro "%s" already exists on "%s".`, str(o["macro"]), str(host["host"]))
+				}
+			}
+			return nil
+		},
+	}
+}
+
+var macroPattern = regexp.MustCompile(`^\{\$[A-Z0-9_.]+(:.*)?\}$`)
+
+func itemKind(name, label string) *kind {
+	k := &kind{
+		name:     name,
+		id:       "itemid",
+		required: []string{"hostid", "key_", "name", "type"},
+		defaults: object{
+			"delay": "0", "status": "0", "state": "0", "value_type": "3", "history": "90d", "trends": "365d",
+			"units": "", "description": "", "error": "", "templateid": "0", "interfaceid": "0", "flags": "0",
+		},
+		hidden: []string{"applications", "preprocessing", "tags"},
+		links: map[string]link{
+			"hostids":      fieldIn("hostid"),
+			"templateids":  fieldIn("hostid"),
+			"groupids":     hostInGroups("hostid"),
+			"interfaceids": fieldIn("interfaceid"),
+		},
+		selects: map[string]selection{
+			"selectHosts":         {key: "hosts", objects: hostOf("hostid")},
+			"selectPreprocessing": {key: "preprocessing", objects: stored("preprocessing")},
+			"selectTags":          {key: "tags", objects: stored("tags")},
+		},
+		prepare:  prepareItem(name),
+		conflict: itemConflict(name, label),
+		deleted: func(s *Server, o object) {
+			for _, name := range []string{"trigger", "triggerprototype"} {
+				for _, t := range s.where(name, func(t object) bool { return contains(refs(t, "functions", "itemid"), str(o["itemid"])) }) {
+					s.remove(name, str(t["triggerid"]))
+				}
+			}
+		},
+	}
+
+	switch name {
+	case "item":
+		k.links["applicationids"] = func(s *Server, o object, ids map[string]bool) bool {
+			for _, id := range list(o["applications"]) {
+				if ids[str(id)] {
+					return true
+				}
+			}
+			return false
+		}
+		k.links["triggerids"] = referredBy("trigger", "functions", "itemid", "itemid")
+		k.selects["selectTriggers"] = selection{key: "triggers", objects: referring("trigger", "functions", "itemid", "itemid")}
+		k.selects["selectApplications"] = selection{key: "applications", objects: func(s *Server, o object) []object {
+			var res []object
+			for _, id := range list(o["applications"]) {
+				if app := s.find("application", str(id)); app != nil {
+					res = append(res, s.public(kinds["application"], app))
+				}
+			}
+			return res
+		}}
+	case "discoveryrule":
+		k.deleteKey = "ruleids"
+		k.defaults["flags"] = "1"
+		k.defaults["lifetime"] = "30d"
+		k.defaults["filter"] = object{"evaltype": "0", "formula": "", "eval_formula": "", "conditions": []interface{}{}}
+		k.hidden = append(k.hidden, "filter", "lld_macro_paths", "overrides")
+		k.selects["selectFilter"] = selection{key: "filter", one: true, objects: stored("filter")}
+		k.selects["selectLLDMacroPaths"] = selection{key: "lld_macro_paths", objects: stored("lld_macro_paths")}
+		k.selects["selectItems"] = selection{key: "items", objects: owned("itemprototype", "ruleid", "itemid")}
+		k.selects["selectTriggers"] = selection{key: "triggers", objects: func(s *Server, o object) []object {
+			return s.publics("triggerprototype", s.where("triggerprototype", func(t object) bool {
+				return s.triggerRule(t) == str(o["itemid"])
+			}))
+		}}
+		k.deleted = func(s *Server, o object) {
+			for _, p := range s.where("itemprototype", func(p object) bool { return p["ruleid"] == o["itemid"] }) {
+				s.remove("itemprototype", str(p["itemid"]))
+			}
+		}
+	case "itemprototype":
+		k.deleteKey = "prototypeids"
+		k.required = []string{"ruleid", "key_", "name", "type"}
+		k.defaults["flags"] = "2"
+		k.links["discoveryids"] = fieldIn("ruleid")
+		k.links["triggerids"] = referredBy("triggerprototype", "functions", "itemid", "itemid")
+		k.selects["selectDiscoveryRule"] = selection{key: "discoveryRule", one: true, objects: byField("discoveryrule", "ruleid")}
+		k.selects["selectTriggers"] = selection{key: "triggers", objects: referring("triggerprototype", "functions", "itemid", "itemid")}
+	}
+	return k
+}
+
+// prepareItem validates the host, rule, interface and applications of an item, LLD rule or item prototype.
+func prepareItem(name string) func(s *Server, o, old object, path string) error {
+	return func(s *Server, o, old object, path string) error {
+		if old != nil {
+			o["hostid"], o["ruleid"] = old["hostid"], old["ruleid"]
+		}
+		if name == "itemprototype" {
+			rule := s.find("discoveryrule", str(o["ruleid"]))
+			if rule == nil {
+				return errNoObject()
+			}
+			o["hostid"] = rule["hostid"]
+		} else {
+			delete(o, "ruleid")
+		}
+		host, hostKind := s.hostOrTemplate(str(o["hostid"]))
+		if host == nil {
+			return errNoObject()
+		}
+		if str(o["key_"]) == "" {
+			return errInvalidParams(`Invalid parameter "%s/key_": cannot be empty.`, path)
+		}
+		if id := str(o["interfaceid"]); hostKind == "host" && id != "" && id != "0" && !contains(refs(host, "interfaces", "interfaceid"), id) {
+			return errInvalidParams(`Invalid parameter "%s/interfaceid": the host interface ID is expected.`, path)
+		}
+		for _, id := range list(o["applications"]) {
+			if app := s.find("application", str(id)); app == nil || app["hostid"] != o["hostid"] {
+				return errNoObject()
+			}
+		}
+		if filter, ok := o["filter"].(object); ok {
+			prepareFilter(filter, "macro")
+		}
+		return nil
+	}
+}
+
+// itemConflict reports items of the same host with the key of o.
+func itemConflict(name, label string) func(s *Server, o object) error {
+	return func(s *Server, o object) error {
+		for _, other := range s.rows(name) {
+			if other["hostid"] == o["hostid"] && other["key_"] == o["key_"] && other["itemid"] != o["itemid"] {
+				host, _ := s.hostOrTemplate(str(o["hostid"]))
+				return errInvalidParams(`%s with key "%s" already exists on "%s".`, label, str(o["key_"]), str(host["host"]))
+			}
+		}
+		return nil
+	}
+}
+
+// prepareFilter sets the formula IDs of the conditions of an LLD rule or action filter and its eval_formula.
+func prepareFilter(filter object, field string) {
+	conditions, _ := filter["conditions"].([]interface{})
+	var ids []string
+	for i, v := range conditions {
+		c, ok := v.(object)
+		if !ok {
+			continue
+		}
+		if str(c["formulaid"]) == "" {
+			c["formulaid"] = string(rune('A' + i%26))
+		}
+		if _, ok := c["operator"]; !ok {
+			c["operator"] = "0"
+			if field == "macro" {
+				c["operator"] = "8"
+			}
+		}
+		ids = append(ids, str(c["formulaid"]))
+	}
+	if _, ok := filter["evaltype"]; !ok {
+		filter["evaltype"] = "0"
+	}
+	if _, ok := filter["formula"]; !ok {
+		filter["formula"] = ""
+	}
+	if filter["conditions"] == nil {
+		filter["conditions"] = []interface{}{}
+	}
+	switch str(filter["evaltype"]) {
+	case "3":
+		filter["eval_formula"] = filter["formula"]
+	case "2":
+		filter["eval_formula"] = strings.Join(ids, " or ")
+	default:
+		filter["eval_formula"] = strings.Join(ids, " and ")
+	}
+}
+
+func triggerKind(name, label string) *kind {
+	items := []string{"item"}
+	if name == "triggerprototype" {
+		items = append(items, "itemprototype")
+	}
+	k := &kind{
+		name:     name,
+		id:       "triggerid",
+		required: []string{"description", "expression"},
+		defaults: object{
+			"comments": "", "priority": "0", "status": "0", "value": "0", "state": "0", "type": "0", "url": "",
+			"recovery_mode": "0", "recovery_expression": "", "correlation_mode": "0", "correlation_tag": "",
+			"manual_close": "0", "error": "", "templateid": "0", "flags": "0",
+		},
+		hidden: []string{"functions", "dependencies", "tags"},
+		links: map[string]link{
+			"hostids":     triggerOn(false),
+			"templateids": triggerOn(false),
+			"groupids":    triggerOn(true),
+			"itemids":     refersTo("functions", "itemid"),
+		},
+		selects: map[string]selection{
+			"selectHosts":        {key: "hosts", objects: triggerHosts},
+			"selectItems":        {key: "items", objects: triggerItems(items)},
+			"selectFunctions":    {key: "functions", objects: stored("functions")},
+			"selectDependencies": {key: "dependencies", objects: joined(name, "dependencies", "triggerid")},
+			"selectTags":         {key: "tags", objects: stored("tags")},
+		},
+		prepare:  prepareTrigger(name, label, items),
+		conflict: triggerConflict(name, label),
+		deleted: func(s *Server, o object) {
+			s.unref(name, "dependencies", "triggerid", str(o["triggerid"]))
+		},
+	}
+	if name == "triggerprototype" {
+		k.defaults["flags"] = "2"
+		delete(k.defaults, "value")
+		delete(k.defaults, "state")
+		k.links["discoveryids"] = func(s *Server, o object, ids map[string]bool) bool { return ids[s.triggerRule(o)] }
+		k.selects["selectDiscoveryRule"] = selection{key: "discoveryRule", one: true, objects: func(s *Server, o object) []object {
+			if rule := s.find("discoveryrule", s.triggerRule(o)); rule != nil {
+				return []object{s.public(kinds["discoveryrule"], rule)}
+			}
+			return nil
+		}}
+	}
+	return k
+}
+
+// prepareTrigger validates a trigger or trigger prototype and links it to the items of its expression.
+func prepareTrigger(name, label string, items []string) func(s *Server, o, old object, path string) error {
+	return func(s *Server, o, old object, path string) error {
+		if str(o["description"]) == "" {
+			return errInvalidParams(`Invalid parameter "%s/description": cannot be empty.`, path)
+		}
+		if old == nil || old["expression"] != o["expression"] {
+			functions, err := s.triggerFunctions(o, items)
+			if err != nil {
+				return err
+			}
+			o["functions"] = functions
+		}
+		if name == "triggerprototype" {
+			prototypes := 0
+			for _, id := range refs(o, "functions", "itemid") {
+				if s.find("itemprototype", id) != nil {
+					prototypes++
+				}
+			}
+			if prototypes == 0 {
+				return errInvalidParams(`%s "%s" must contain at least one item prototype.`, label, str(o["description"]))
+			}
+		}
+		return s.checkRefs(o, "dependencies", "triggerid", name)
+	}
+}
+
+// triggerFunctions returns the functions of the expression of trigger o, with the IDs of their items.
+func (s *Server) triggerFunctions(o object, items []string) ([]interface{}, error) {
+	refs := expressionRefs(str(o["expression"]))
+	if len(refs) == 0 {
+		return nil, errInvalidParams("Trigger expression must contain at least one /host/key reference.")
+	}
+	var functions []interface{}
+	for _, ref := range refs {
+		host, _ := s.hostByName(ref.host)
+		if host == nil {
+			return nil, errInvalidParams(`Incorrect trigger expression. Host "%s" does not exist or you have no access to this host.`, ref.host)
+		}
+		var item object
+		for _, name := range items {
+			for _, i := range s.rows(name) {
+				if i["hostid"] == host[hostIDField(host)] && str(i["key_"]) == ref.key {
+					item = i
+				}
+			}
+		}
+		if item == nil {
+			return nil, errInvalidParams(`Incorrect item key "%s" provided for trigger expression on "%s".`, ref.key, ref.host)
+		}
+		functions = append(functions, object{
+			"functionid": s.nextID("functionid"),
+			"itemid":     item["itemid"],
+			"triggerid":  o["triggerid"],
+			"function":   ref.function,
+			"parameter":  ref.parameter,
+		})
+	}
+	return functions, nil
+}
+
+// triggerConflict reports triggers of the same hosts with the description and expression of o.
+func triggerConflict(name, label string) func(s *Server, o object) error {
+	return func(s *Server, o object) error {
+		hosts := map[string]bool{}
+		for _, h := range triggerHosts(s, o) {
+			hosts[str(h[hostIDField(h)])] = true
+		}
+		for _, other := range s.rows(name) {
+			if other["triggerid"] == o["triggerid"] || other["description"] != o["description"] || other["expression"] != o["expression"] {
+				continue
+			}
+			for _, h := range triggerHosts(s, other) {
+				if hosts[str(h[hostIDField(h)])] {
+					return errInvalidParams(`%s "%s" already exists on "%s".`, label, str(o["description"]), str(h["host"]))
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// triggerHosts returns the hosts and templates of the items of trigger o.
+func triggerHosts(s *Server, o object) []object {
+	var res []object
+	seen := map[string]bool{}
+	for _, id := range refs(o, "functions", "itemid") {
+		item := s.item(id)
+		if item == nil || seen[str(item["hostid"])] {
+			continue
+		}
+		seen[str(item["hostid"])] = true
+		if host, name := s.hostOrTemplate(str(item["hostid"])); host != nil {
+			res = append(res, s.public(kinds[name], host))
+		}
+	}
+	return res
+}
+
+// triggerItems returns the items, among the APIs of names, of the functions of a trigger.
+func triggerItems(names []string) func(s *Server, o object) []object {
+	return func(s *Server, o object) []object {
+		var res []object
+		for _, id := range refs(o, "functions", "itemid") {
+			for _, name := range names {
+				if item := s.find(name, id); item != nil {
+					res = append(res, s.public(kinds[name], item))
+				}
+			}
+		}
+		return res
+	}
+}
+
+// triggerOn returns the link of triggers to the hosts and templates of their items,
+// or to the groups of these hosts.
+func triggerOn(groups bool) link {
+	return func(s *Server, o object, ids map[string]bool) bool {
+		for _, h := range triggerHosts(s, o) {
+			if groups && hasRef(s.find(hostKindOf(h), str(h[hostIDField(h)])), "groups", "groupid", ids) ||
+				!groups && ids[str(h[hostIDField(h)])] {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// triggerRule returns the LLD rule of the item prototypes of a trigger prototype.
+func (s *Server) triggerRule(o object) string {
+	for _, id := range refs(o, "functions", "itemid") {
+		if p := s.find("itemprototype", id); p != nil {
+			return str(p["ruleid"])
+		}
+	}
+	return ""
+}
+
+// hostTriggers returns the selection of the triggers of a host or template.
+func hostTriggers(name, idField string) func(s *Server, o object) []object {
+	return func(s *Server, o object) []object {
+		return s.publics(name, s.where(name, func(t object) bool {
+			return triggerOn(false)(s, t, map[string]bool{str(o[idField]): true})
+		}))
+	}
+}
+
+// item returns the item or item prototype with ID id.
+func (s *Server) item(id string) object {
+	if item := s.find("item", id); item != nil {
+		return item
+	}
+	return s.find("itemprototype", id)
+}
+
+// exprRef is a reference of a trigger expression to an item.
+type exprRef struct {
+	host, key, function, parameter string
+}
+
+// oldExprRef matches item references of trigger expressions before Zabbix 5.4.
+var oldExprRef = regexp.MustCompile(`\{([^{}:]+):(.+?)\.(\w+)\(([^)]*)\)\}`)
+
+// expressionRefs returns the item references of a trigger expression:
+// func(/host/key,params) from Zabbix 5.4 and {host:key.func(params)} before.
+func expressionRefs(expr string) (refs []exprRef) {
+	for i := 0; i+1 < len(expr); i++ {
+		if expr[i] != '(' || expr[i+1] != '/' {
+			continue
+		}
+		start := i
+		for start > 0 && (isIdent(expr[start-1])) {
+			start--
+		}
+		rest := expr[i+2:]
+		slash := strings.IndexByte(rest, '/')
+		if slash < 0 {
+			break
+		}
+		ref := exprRef{host: rest[:slash], function: expr[start:i]}
+		rest = rest[slash+1:]
+
+		end := scanUntil(rest, ",)")
+		ref.key = rest[:end]
+		ref.parameter = "$"
+		if end < len(rest) && rest[end] == ',' {
+			params := rest[end+1:]
+			paramsEnd := scanUntil(params, ")")
+			ref.parameter += "," + params[:paramsEnd]
+			end += 1 + paramsEnd
+		}
+		refs = append(refs, ref)
+		i += 2 + slash + 1 + end
+	}
+	if len(refs) > 0 {
+		return refs
+	}
+	for _, m := range oldExprRef.FindAllStringSubmatch(expr, -1) {
+		refs = append(refs, exprRef{host: m[1], key: m[2], function: m[3], parameter: m[4]})
+	}
+	return refs
+}
+
+// scanUntil returns the index of the first of stops in s outside of brackets and quotes, or len(s).
+func scanUntil(s, stops string) int {
+	depth, quoted := 0, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quoted && c == '\\':
+			i++
+		case quoted:
+			quoted = c != '"'
+		case c == '"':
+			quoted = true
+		case c == '[' || c == '(':
+			depth++
+		case (c == ']' || c == ')') && depth > 0:
+			depth--
+		case depth == 0 && strings.IndexByte(stops, c) >= 0:
+			return i
+		}
+	}
+	return len(s)
+}
+
+func isIdent(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func applicationKind() *kind {
+	return &kind{
+		name:     "application",
+		id:       "applicationid",
+		until:    "5.4",
+		required: []string{"hostid", "name"},
+		defaults: object{"flags": "0"},
+		links: map[string]link{
+			"hostids":     fieldIn("hostid"),
+			"templateids": fieldIn("hostid"),
+			"groupids":    hostInGroups("hostid"),
+			"itemids": func(s *Server, o object, ids map[string]bool) bool {
+				for id := range ids {
+					if item := s.find("item", id); item != nil && contains(strings.Fields(listString(item["applications"])), str(o["applicationid"])) {
+						return true
+					}
+				}
+				return false
+			},
+		},
+		selects: map[string]selection{
+			"selectHost": {key: "host", one: true, objects: hostOf("hostid")},
+			"selectItems": {key: "items", objects: func(s *Server, o object) []object {
+				return s.publics("item", s.where("item", func(i object) bool {
+					return contains(strings.Fields(listString(i["applications"])), str(o["applicationid"]))
+				}))
+			}},
+		},
+		prepare: func(s *Server, o, old object, path string) error {
+			if old != nil {
+				o["hostid"] = old["hostid"]
+			}
+			if host, _ := s.hostOrTemplate(str(o["hostid"])); host == nil {
+				return errNoObject()
+			}
+			return nil
+		},
+		conflict: func(s *Server, o object) error {
+			for _, other := range s.rows("application") {
+				if other["hostid"] == o["hostid"] && other["name"] == o["name"] && other["applicationid"] != o["applicationid"] {
+					return errInvalidParams(`Application "%s" already exists.`, str(o["name"]))
+				}
+			}
+			return nil
+		},
+		deleted: func(s *Server, o object) {
+			for _, item := range s.rows("item") {
+				var apps []interface{}
+				for _, id := range list(item["applications"]) {
+					if str(id) != str(o["applicationid"]) {
+						apps = append(apps, id)
+					}
+				}
+				if item["applications"] != nil {
+					item["applications"] = apps
+				}
+			}
+		},
+	}
+}
+
+// listString returns the strings of an array separated by spaces.
+func listString(v interface{}) string {
+	var res []string
+	for _, value := range list(v) {
+		res = append(res, str(value))
+	}
+	return strings.Join(res, " ")
+}
+
+func actionKind() *kind {
+	return &kind{
+		name:     "action",
+		id:       "actionid",
+		required: []string{"name", "eventsource"},
+		defaults: object{"status": "0", "esc_period": "1h"},
+		hidden:   []string{"filter", "operations", "recovery_operations", "update_operations"},
+		readonly: []string{"eventsource"},
+		selects: map[string]selection{
+			"selectFilter":             {key: "filter", one: true, objects: stored("filter")},
+			"selectOperations":         {key: "operations", objects: stored("operations")},
+			"selectRecoveryOperations": {key: "recovery_operations", objects: stored("recovery_operations")},
+			"selectUpdateOperations":   {key: "update_operations", objects: stored("update_operations")},
+		},
+		prepare:  prepareAction,
+		conflict: unique("action", `Action "%s" already exists.`, "name"),
+	}
+}
+
+// prepareAction validates an action and gives IDs to its conditions and operations.
+func prepareAction(s *Server, o, old object, path string) error {
+	sources := []string{"0", "1", "2", "3"}
+	if s.atLeast("6.4") {
+		sources = append(sources, "4")
+	}
+	if !contains(sources, str(o["eventsource"])) {
+		return errInvalidParams(`Invalid parameter "%s/eventsource": value must be one of %s.`, path, strings.Join(sources, ", "))
+	}
+	if old == nil && str(o["eventsource"]) == "0" {
+		setDefaults(o, object{"pause_suppressed": "1", "notify_if_canceled": "1"})
+	}
+
+	filter, ok := o["filter"].(object)
+	if !ok {
+		filter = object{}
+		o["filter"] = filter
+	}
+	prepareFilter(filter, "conditiontype")
+	for _, v := range list(filter["conditions"]) {
+		if c, ok := v.(object); ok {
+			if str(c["conditionid"]) == "" {
+				c["conditionid"] = s.nextID("conditionid")
+			}
+			c["actionid"] = o["actionid"]
+		}
+	}
+
+	for _, key := range []string{"operations", "recovery_operations", "update_operations"} {
+		for i, v := range list(o[key]) {
+			op, ok := v.(object)
+			if !ok {
+				return errInvalidParams(`Invalid parameter "%s/%s/%d": an array is expected.`, path, key, i+1)
+			}
+			if err := s.prepareOperation(o, op, key == "operations"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// prepareOperation checks the objects an action operation refers to, and gives it an ID.
+func (s *Server) prepareOperation(action, op object, escalated bool) error {
+	for _, ref := range []struct{ key, field, name string }{
+		{"opgroup", "groupid", "hostgroup"},
+		{"optemplate", "templateid", "template"},
+		{"opmessage_usr", "userid", "user"},
+		{"opmessage_grp", "usrgrpid", "usergroup"},
+		{"opcommand_hst", "hostid", "host"},
+		{"opcommand_grp", "groupid", "hostgroup"},
+	} {
+		for _, id := range refs(op, ref.key, ref.field) {
+			if id != "0" && s.find(ref.name, id) == nil {
+				return errNoObject()
+			}
+		}
+	}
+	if message, ok := op["opmessage"].(object); ok {
+		if id := str(message["mediatypeid"]); id != "" && id != "0" && s.find("mediatype", id) == nil {
+			return errNoObject()
+		}
+	}
+
+	if str(op["operationid"]) == "" {
+		op["operationid"] = s.nextID("operationid")
+	}
+	op["actionid"] = action["actionid"]
+	if escalated {
+		setDefaults(op, object{"esc_period": "0", "esc_step_from": "1", "esc_step_to": "1", "evaltype": "0"})
+	}
+	for _, v := range op {
+		switch v := v.(type) {
+		case object:
+			v["operationid"] = op["operationid"]
+		case []interface{}:
+			for _, e := range v {
+				if e, ok := e.(object); ok {
+					e["operationid"] = op["operationid"]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func mediaTypeKind() *kind {
+	return &kind{
+		name:     "mediatype",
+		id:       "mediatypeid",
+		required: []string{"name", "type"},
+		defaults: object{"status": "0", "description": "", "maxsessions": "1", "maxattempts": "3", "attempt_interval": "10s"},
+		hidden:   []string{"message_templates"},
+		links: map[string]link{
+			"userids": referredBy("user", "medias", "mediatypeid", "mediatypeid"),
+		},
+		selects: map[string]selection{
+			"selectUsers":            {key: "users", objects: referring("user", "medias", "mediatypeid", "mediatypeid")},
+			"selectMessageTemplates": {key: "message_templates", objects: stored("message_templates")},
+		},
+		conflict: unique("mediatype", `Media type "%s" already exists.`, "name"),
+		deleted: func(s *Server, o object) {
+			for _, u := range s.rows("user") {
+				var medias []interface{}
+				for _, m := range list(u["medias"]) {
+					if m, ok := m.(object); !ok || m["mediatypeid"] != o["mediatypeid"] {
+						medias = append(medias, m)
+					}
+				}
+				u["medias"] = medias
+			}
+		},
+	}
+}
+
+func roleKind() *kind {
+	return &kind{
+		name:     "role",
+		id:       "roleid",
+		since:    "5.2",
+		required: []string{"name", "type"},
+		defaults: object{"readonly": "0"},
+		hidden:   []string{"rules"},
+		links: map[string]link{
+			"userids": func(s *Server, o object, ids map[string]bool) bool {
+				for id := range ids {
+					if u := s.find("user", id); u != nil && u["roleid"] == o["roleid"] {
+						return true
+					}
+				}
+				return false
+			},
+		},
+		selects: map[string]selection{
+			"selectRules": {key: "rules", one: true, objects: stored("rules")},
+			"selectUsers": {key: "users", objects: owned("user", "roleid", "roleid")},
+		},
+		prepare: func(s *Server, o, old object, path string) error {
+			if old != nil && old["readonly"] == "1" {
+				return errInvalidParams(`Cannot update readonly user role "%s".`, str(old["name"]))
+			}
+			if !contains([]string{"1", "2", "3"}, str(o["type"])) {
+				return errInvalidParams(`Invalid parameter "%s/type": value must be one of 1, 2, 3.`, path)
+			}
+			return nil
+		},
+		conflict: unique("role", `User role with name "%s" already exists.`, "name"),
+		deleting: func(s *Server, ids map[string]bool) error {
+			for id := range ids {
+				role := s.find("role", id)
+				if role["readonly"] == "1" {
+					return errInvalidParams(`Cannot delete readonly user role "%s".`, str(role["name"]))
+				}
+				if len(s.where("user", func(u object) bool { return u["roleid"] == role["roleid"] })) > 0 {
+					return errInvalidParams(`Cannot delete assigned user role "%s".`, str(role["name"]))
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func userKind() *kind {
+	return &kind{
+		name:     "user",
+		id:       "userid",
+		required: []string{"usrgrps"},
+		defaults: object{
+			"name": "", "surname": "", "url": "", "autologin": "0", "autologout": "15m", "lang": "default",
+			"refresh": "30s", "theme": "default", "attempt_failed": "0", "attempt_ip": "", "attempt_clock": "0",
+			"rows_per_page": "50", "timezone": "default",
+		},
+		hidden: []string{"passwd", "usrgrps", "medias"},
+		links: map[string]link{
+			"usrgrpids":    refersTo("usrgrps", "usrgrpid"),
+			"mediaids":     refersTo("medias", "mediaid"),
+			"mediatypeids": refersTo("medias", "mediatypeid"),
+		},
+		selects: map[string]selection{
+			"selectUsrgrps":    {key: "usrgrps", objects: joined("usergroup", "usrgrps", "usrgrpid")},
+			"selectMedias":     {key: "medias", objects: stored("medias")},
+			"selectMediatypes": {key: "mediatypes", objects: joined("mediatype", "medias", "mediatypeid")},
+			"selectRole":       {key: "role", one: true, objects: byField("role", "roleid")},
+		},
+		prepare:  prepareUser,
+		conflict: userConflict,
+		deleted: func(s *Server, o object) {
+			for session, id := range s.sessions {
+				if id == str(o["userid"]) {
+					delete(s.sessions, session)
+				}
+			}
+		},
+	}
+}
+
+// prepareUser validates a user, its role, groups and media.
+func prepareUser(s *Server, o, old object, path string) error {
+	delete(o, "current_passwd")
+	required := []string{s.userNameField()}
+	if s.atLeast("5.2") {
+		required = append(required, "roleid")
+	} else {
+		required = append(required, "type")
+	}
+	for _, field := range required {
+		if str(o[field]) == "" {
+			if old == nil {
+				return errInvalidParams(`Invalid parameter "%s": the parameter "%s" is missing.`, path, field)
+			}
+			return errInvalidParams(`Invalid parameter "%s/%s": cannot be empty.`, path, field)
+		}
+	}
+	if s.atLeast("5.2") && s.find("role", str(o["roleid"])) == nil {
+		return errNoObject()
+	}
+	if err := s.checkRefs(o, "usrgrps", "usrgrpid", "usergroup"); err != nil {
+		return err
+	}
+
+	for i, v := range list(o["medias"]) {
+		m, ok := v.(object)
+		if !ok {
+			return errInvalidParams(`Invalid parameter "%s/medias/%d": an array is expected.`, path, i+1)
+		}
+		if s.find("mediatype", str(m["mediatypeid"])) == nil {
+			return errNoObject()
+		}
+		if str(m["mediaid"]) == "" {
+			m["mediaid"] = s.nextID("mediaid")
+		}
+		m["userid"] = o["userid"]
+		setDefaults(m, object{"active": "0", "severity": "63", "period": "1-7,00:00-24:00"})
+	}
+	return nil
+}
+
+// userConflict reports users with the name of o.
+func userConflict(s *Server, o object) error {
+	field := s.userNameField()
+	for _, other := range s.rows("user") {
+		if other[field] == o[field] && other["userid"] != o["userid"] {
+			return errInvalidParams(`User with %s "%s" already exists.`, field, str(o[field]))
+		}
+	}
+	return nil
+}
+
+func userGroupKind() *kind {
+	return &kind{
+		name:     "usergroup",
+		id:       "usrgrpid",
+		required: []string{"name"},
+		defaults: object{"gui_access": "0", "users_status": "0", "debug_mode": "0"},
+		hidden:   []string{"rights", "hostgroup_rights", "templategroup_rights", "tag_filters"},
+		links: map[string]link{
+			"userids": referredBy("user", "usrgrps", "usrgrpid", "usrgrpid"),
+		},
+		selects: map[string]selection{
+			"selectUsers":               {key: "users", objects: referring("user", "usrgrps", "usrgrpid", "usrgrpid")},
+			"selectRights":              {key: "rights", objects: stored("rights")},
+			"selectHostGroupRights":     {key: "hostgroup_rights", objects: stored("hostgroup_rights")},
+			"selectTemplateGroupRights": {key: "templategroup_rights", objects: stored("templategroup_rights")},
+			"selectTagFilters":          {key: "tag_filters", objects: stored("tag_filters")},
+		},
+		conflict: unique("usergroup", `User group "%s" already exists.`, "name"),
+		saved: func(s *Server, o object, path string) error {
+			users, ok := o["users"]
+			delete(o, "users")
+			if !ok {
+				return nil
+			}
+			members := map[string]bool{}
+			for _, id := range refs(object{"users": users}, "users", "userid") {
+				if s.find("user", id) == nil {
+					return errNoObject()
+				}
+				members[id] = true
+			}
+			id := str(o["usrgrpid"])
+			s.unref("user", "usrgrps", "usrgrpid", id)
+			for userID := range members {
+				u := s.find("user", userID)
+				u["usrgrps"] = append(list(u["usrgrps"]), object{"usrgrpid": id})
+			}
+			return nil
+		},
+		deleting: func(s *Server, ids map[string]bool) error {
+			return s.mustKeep("user", "usrgrps", "usrgrpid", ids, `User "%s" cannot be without user group.`)
+		},
+		deleted: func(s *Server, o object) {
+			s.unref("user", "usrgrps", "usrgrpid", str(o["usrgrpid"]))
+		},
+	}
+}
+
+// unique returns the conflict of objects of the API name having the same value of field.
+func unique(name, format, field string) func(s *Server, o object) error {
+	id := kinds[name]
+	return func(s *Server, o object) error {
+		if id == nil {
+			id = kinds[name]
+		}
+		for _, other := range s.rows(name) {
+			if other[field] == o[field] && other[id.id] != o[id.id] {
+				return errInvalidParams(format, str(o[field]))
+			}
+		}
+		return nil
+	}
+}
+
+// fieldIn links objects whose field is one of the IDs.
+func fieldIn(field string) link {
+	return func(s *Server, o object, ids map[string]bool) bool {
+		return ids[str(o[field])]
+	}
+}
+
+// refersTo links objects referring to one of the IDs in the array o[key].
+func refersTo(key, field string) link {
+	return func(s *Server, o object, ids map[string]bool) bool {
+		return hasRef(o, key, field, ids)
+	}
+}
+
+// referredBy links objects referred to by one of the objects of the API name with the IDs,
+// in their array key, like host groups referred to by hosts.
+func referredBy(name, key, field, idField string) link {
+	return func(s *Server, o object, ids map[string]bool) bool {
+		for id := range ids {
+			if other := s.find(name, id); other != nil && contains(refs(other, key, field), str(o[idField])) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// hostInGroups links objects whose host or template, in field, is in one of the groups.
+func hostInGroups(field string) link {
+	return func(s *Server, o object, ids map[string]bool) bool {
+		host, _ := s.hostOrTemplate(str(o[field]))
+		return host != nil && hasRef(host, "groups", "groupid", ids)
+	}
+}
+
+// joined selects the objects of the API name referred to in the array o[key].
+func joined(name, key, field string) func(s *Server, o object) []object {
+	return func(s *Server, o object) []object {
+		var res []object
+		for _, id := range refs(o, key, field) {
+			if other := s.find(name, id); other != nil {
+				res = append(res, s.public(kinds[name], other))
+			}
+		}
+		return res
+	}
+}
+
+// referring selects the objects of the API name referring to o in their array key.
+func referring(name, key, field, idField string) func(s *Server, o object) []object {
+	return func(s *Server, o object) []object {
+		return s.publics(name, s.where(name, func(other object) bool {
+			return contains(refs(other, key, field), str(o[idField]))
+		}))
+	}
+}
+
+// owned selects the objects of the API name whose field is the ID of o.
+func owned(name, field, idField string) func(s *Server, o object) []object {
+	return func(s *Server, o object) []object {
+		return s.publics(name, s.where(name, func(other object) bool { return str(other[field]) == str(o[idField]) }))
+	}
+}
+
+// byField selects the object of the API name whose ID is in the field of o.
+func byField(name, field string) func(s *Server, o object) []object {
+	return func(s *Server, o object) []object {
+		if other := s.find(name, str(o[field])); other != nil {
+			return []object{s.public(kinds[name], other)}
+		}
+		return nil
+	}
+}
+
+// hostOf selects the host or template whose ID is in the field of o.
+func hostOf(field string) func(s *Server, o object) []object {
+	return func(s *Server, o object) []object {
+		if host, name := s.hostOrTemplate(str(o[field])); host != nil {
+			return []object{s.public(kinds[name], host)}
+		}
+		return nil
+	}
+}
+
+// stored selects the objects stored in o[key], an array or an object.
+func stored(key string) func(s *Server, o object) []object {
+	return func(s *Server, o object) []object {
+		var res []object
+		for _, v := range list(o[key]) {
+			if v, ok := v.(object); ok {
+				res = append(res, deepCopy(v).(object))
+			}
+		}
+		return res
+	}
+}
+
+// hostOrTemplate returns the host or template with ID id, and its API name.
+func (s *Server) hostOrTemplate(id string) (object, string) {
+	if host := s.find("host", id); host != nil {
+		return host, "host"
+	}
+	if template := s.find("template", id); template != nil {
+		return template, "template"
+	}
+	return nil, ""
+}
+
+// hostByName returns the host or template with the technical name name, and its API name.
+func (s *Server) hostByName(name string) (object, string) {
+	for _, api := range []string{"host", "template"} {
+		for _, o := range s.rows(api) {
+			if str(o["host"]) == name {
+				return o, api
+			}
+		}
+	}
+	return nil, ""
+}
+
+// hostIDField returns the ID field of a host or template.
+func hostIDField(o object) string {
+	if _, ok := o["templateid"]; ok {
+		return "templateid"
+	}
+	return "hostid"
+}
+
+// hostKindOf returns the API name of a host or template.
+func hostKindOf(o object) string {
+	if hostIDField(o) == "templateid" {
+		return "template"
+	}
+	return "host"
+}
+
+// checkRefs checks the objects referred to in the array o[key] exist in the API name,
+// and keeps only their IDs.
+func (s *Server) checkRefs(o object, key, field, name string) error {
+	if o[key] == nil {
+		return nil
+	}
+	var ids []interface{}
+	for _, id := range refs(o, key, field) {
+		if s.find(name, id) == nil {
+			return errNoObject()
+		}
+		ids = append(ids, object{field: id})
+	}
+	if len(ids) != len(list(o[key])) {
+		return errInvalidParams(`Invalid parameter "/1/%s": an array of objects with "%s" is expected.`, key, field)
+	}
+	o[key] = ids
+	return nil
+}
+
+// mustKeep reports objects of the API name which would lose all their references in the array key
+// if the objects with IDs ids were deleted.
+func (s *Server) mustKeep(name, key, field string, ids map[string]bool, format string) error {
+	for _, o := range s.rows(name) {
+		refs := refs(o, key, field)
+		kept := 0
+		for _, id := range refs {
+			if !ids[id] {
+				kept++
+			}
+		}
+		if len(refs) > 0 && kept == 0 {
+			label := o["host"]
+			if name == "user" {
+				label = o[s.userNameField()]
+			}
+			return errInvalidParams(format, str(label))
+		}
+	}
+	return nil
+}
+
+// unref removes the references to id from the arrays key of the objects of the API name.
+func (s *Server) unref(name, key, field, id string) {
+	for _, o := range s.rows(name) {
+		values, ok := o[key].([]interface{})
+		if !ok {
+			continue
+		}
+		kept := []interface{}{}
+		for _, v := range values {
+			if ref, ok := v.(object); !ok || str(ref[field]) != id {
+				kept = append(kept, v)
+			}
+		}
+		o[key] = kept
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
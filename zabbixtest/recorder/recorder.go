@@ -0,0 +1,388 @@
+// Package recorder records the JSON-RPC exchanges of a Zabbix API client to cassette files
+// and replays them, so code using the API can be tested offline against real server behavior.
+//
+//	rec, err := recorder.New("testdata/zabbix-7.0.json", recorder.ModeAuto)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer rec.Stop()
+//	api, err := zabbix.NewAPIWithOptions(url, zabbix.WithHTTPClient(rec.Client()))
+//
+// Requests are matched on their method and params, ignoring the volatile "id" and "auth"
+// fields. Secrets like passwords and session tokens are scrubbed before cassettes are saved.
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Mode tells whether a Recorder talks to a real server.
+type Mode int
+
+const (
+	// ModeReplay serves the exchanges of an existing cassette, and fails on unknown requests.
+	ModeReplay Mode = iota
+	// ModeRecord sends requests to the server and saves the exchanges to the cassette on Stop.
+	ModeRecord
+	// ModeAuto replays the cassette if it exists and records it otherwise.
+	ModeAuto
+)
+
+// ScrubbedValue replaces secrets in cassettes.
+const ScrubbedValue = "[SCRUBBED]"
+
+// scrubFields are the keys scrubbed at any depth of requests and responses.
+var scrubFields = []string{
+	"password", "passwd", "current_passwd", "token", "sessionid",
+	"snmp_community", "snmpv3_authpassphrase", "snmpv3_privpassphrase",
+	"privatekey", "client_secret", "access_token", "refresh_token",
+}
+
+// Cassette is the content of a cassette file.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one recorded HTTP exchange. Request and Response are JSON-RPC bodies
+// without ids, responses of batches being in the order of their requests.
+// Body holds the response instead when it is not JSON, like HTML error pages.
+type Interaction struct {
+	Request  json.RawMessage `json:"request"`
+	Status   int             `json:"status"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Body     string          `json:"body,omitempty"`
+}
+
+// Option configures a Recorder.
+type Option func(r *Recorder)
+
+// WithTransport Sends recorded requests with rt instead of http.DefaultTransport.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = rt
+	}
+}
+
+// WithScrubFields Scrubs the values of extra keys, at any depth of requests and responses.
+func WithScrubFields(keys ...string) Option {
+	return func(r *Recorder) {
+		for _, key := range keys {
+			r.scrub[strings.ToLower(key)] = true
+		}
+	}
+}
+
+// Recorder is an http.RoundTripper recording or replaying JSON-RPC exchanges.
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	scrub     map[string]bool
+
+	mu       sync.Mutex
+	cassette Cassette
+	keys     []string // match keys of the cassette interactions
+	used     []bool
+}
+
+// New Creates a Recorder of the cassette file at path.
+// In ModeReplay, and in ModeAuto when the file exists, the cassette is loaded.
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode, transport: http.DefaultTransport, scrub: map[string]bool{}}
+	for _, key := range scrubFields {
+		r.scrub[key] = true
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.mode == ModeAuto {
+		r.mode = ModeReplay
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			r.mode = ModeRecord
+		}
+	}
+	if r.mode == ModeRecord {
+		return r, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, &r.cassette); err != nil {
+		return nil, fmt.Errorf("recorder: cannot parse cassette %s: %w", path, err)
+	}
+	for _, in := range r.cassette.Interactions {
+		key, err := r.matchKey(in.Request)
+		if err != nil {
+			return nil, fmt.Errorf("recorder: cannot parse cassette %s: %w", path, err)
+		}
+		r.keys = append(r.keys, key)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+// Mode Returns ModeRecord or ModeReplay, ModeAuto being resolved by New.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client Returns an HTTP client using the recorder, for zabbix.WithHTTPClient or API.SetClient.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Stop Saves the recorded cassette in ModeRecord, it does nothing in ModeReplay.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(b, '\n'), 0o644)
+}
+
+// RoundTrip Records or replays req.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if r.mode == ModeRecord {
+		return r.record(req, body)
+	}
+	return r.replay(req, body)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	res, err := r.transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	b, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(b))
+
+	request, ids, err := r.normalize(body, nil)
+	if err != nil {
+		return nil, err
+	}
+	in := Interaction{Request: request, Status: res.StatusCode}
+	if in.Response, _, err = r.normalize(b, ids); err != nil {
+		in.Body = string(b)
+	}
+	var call struct {
+		Method string `json:"method"`
+	}
+	if json.Unmarshal(request, &call) == nil && strings.EqualFold(call.Method, "user.login") {
+		in.Response = scrubResult(in.Response)
+	}
+	key, _ := r.matchKey(request)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, in)
+	r.keys = append(r.keys, key)
+	r.used = append(r.used, true)
+	return res, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	request, ids, err := r.normalize(body, nil)
+	if err != nil {
+		return nil, err
+	}
+	key, err := r.matchKey(request)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	// The first unused interaction matching the request is served, so that repeated
+	// requests get the successive recorded responses, the last one being served again.
+	found := -1
+	for i, k := range r.keys {
+		if k == key {
+			found = i
+			if !r.used[i] {
+				break
+			}
+		}
+	}
+	if found < 0 {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("recorder: no interaction of %s matches request %s", r.path, request)
+	}
+	r.used[found] = true
+	in := r.cassette.Interactions[found]
+	r.mu.Unlock()
+
+	response := []byte(in.Body)
+	if in.Response != nil {
+		if response, err = withIDs(in.Response, ids); err != nil {
+			return nil, err
+		}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", in.Status, http.StatusText(in.Status)),
+		StatusCode:    in.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(response)),
+		ContentLength: int64(len(response)),
+		Request:       req,
+	}, nil
+}
+
+// normalize removes the ids and auth of the JSON-RPC body b and scrubs its secrets.
+// Requests return their ids; responses are given the ids of their requests,
+// to put batch responses in the order of the requests.
+func (r *Recorder) normalize(b []byte, requestIDs []interface{}) (normalized json.RawMessage, ids []interface{}, err error) {
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err = d.Decode(&v); err != nil {
+		return nil, nil, fmt.Errorf("recorder: not a JSON-RPC body: %w", err)
+	}
+
+	objects, batch := v.([]interface{})
+	if !batch {
+		objects = []interface{}{v}
+	}
+	if requestIDs != nil {
+		objects = sortByIDs(objects, requestIDs)
+	}
+	for _, o := range objects {
+		m, ok := o.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ids = append(ids, m["id"])
+		delete(m, "id")
+		delete(m, "auth")
+		r.scrubValue(m)
+	}
+	if batch {
+		v = objects
+	} else {
+		v = objects[0]
+	}
+	normalized, err = json.Marshal(v)
+	return
+}
+
+func (r *Recorder) scrubValue(v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if _, isString := value.(string); isString && r.scrub[strings.ToLower(key)] {
+				v[key] = ScrubbedValue
+			} else {
+				r.scrubValue(value)
+			}
+		}
+	case []interface{}:
+		for _, value := range v {
+			r.scrubValue(value)
+		}
+	}
+}
+
+// matchKey returns the key of a normalized request, its canonical JSON.
+func (r *Recorder) matchKey(request json.RawMessage) (string, error) {
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(request))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return "", err
+	}
+	key, err := json.Marshal(v)
+	return string(key), err
+}
+
+// scrubResult scrubs the result of a user.login response, the session id.
+// With userData, the session id is the "sessionid" field, which is scrubbed already.
+func scrubResult(response json.RawMessage) json.RawMessage {
+	var m map[string]json.RawMessage
+	if json.Unmarshal(response, &m) != nil {
+		return response
+	}
+	var result string
+	if json.Unmarshal(m["result"], &result) != nil {
+		return response
+	}
+	m["result"], _ = json.Marshal(ScrubbedValue)
+	scrubbed, err := json.Marshal(m)
+	if err != nil {
+		return response
+	}
+	return scrubbed
+}
+
+// sortByIDs orders the responses of a batch like the requests of ids.
+func sortByIDs(responses []interface{}, ids []interface{}) []interface{} {
+	sorted := make([]interface{}, 0, len(responses))
+	taken := make([]bool, len(responses))
+	for _, id := range ids {
+		for i, response := range responses {
+			if m, ok := response.(map[string]interface{}); ok && !taken[i] && fmt.Sprint(m["id"]) == fmt.Sprint(id) {
+				sorted = append(sorted, response)
+				taken[i] = true
+				break
+			}
+		}
+	}
+	for i, response := range responses {
+		if !taken[i] {
+			sorted = append(sorted, response)
+		}
+	}
+	return sorted
+}
+
+// withIDs sets the ids of the JSON-RPC responses of response, in order.
+func withIDs(response json.RawMessage, ids []interface{}) ([]byte, error) {
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(response))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	objects, batch := v.([]interface{})
+	if !batch {
+		objects = []interface{}{v}
+	}
+	for i, o := range objects {
+		if m, ok := o.(map[string]interface{}); ok && i < len(ids) {
+			m["id"] = ids[i]
+		}
+	}
+	return json.Marshal(v)
+}
@@ -0,0 +1,120 @@
+package recorder_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	zapi "github.com/claranet/go-zabbix-api"
+	"github.com/claranet/go-zabbix-api/zabbixtest/recorder"
+)
+
+// testServer answers like Zabbix 6.0, host.get returning one more host on every call.
+func testServer(t *testing.T) *httptest.Server {
+	hosts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Method string `json:"method"`
+			ID     int    `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		switch strings.ToLower(req.Method) {
+		case "apiinfo.version":
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","result":"6.0.0","id":%d}`, req.ID)
+		case "user.login":
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","result":"0424bd59b807674191e7d77572075f33","id":%d}`, req.ID)
+		case "host.get":
+			hosts++
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","result":[{"hostid":"%d"}],"id":%d}`, hosts, req.ID)
+		default:
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","error":{"code":-32601,"message":"Method not found.","data":"Incorrect API \"%s\"."},"id":%d}`, req.Method, req.ID)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// testSession logs in and gets hosts twice, returning the IDs of the hosts.
+func testSession(t *testing.T, url string, rec *recorder.Recorder) []string {
+	api, err := zapi.NewAPIWithOptions(url, zapi.WithHTTPClient(rec.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = api.Login("Admin", "zabbix"); err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for i := 0; i < 2; i++ {
+		hosts, err := api.HostsGet(zapi.Params{"output": "hostid"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, hosts[0].HostID)
+	}
+	return ids
+}
+
+func TestRecordReplay(t *testing.T) {
+	srv := testServer(t)
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	rec, err := recorder.New(path, recorder.ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Mode() != recorder.ModeRecord {
+		t.Fatalf("Mode = %v, want ModeRecord without a cassette", rec.Mode())
+	}
+	recorded := testSession(t, srv.URL, rec)
+	if err = rec.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{`"zabbix"`, "0424bd59b807674191e7d77572075f33", `"id"`, `"auth"`} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("cassette contains %s:\n%s", secret, b)
+		}
+	}
+
+	srv.Close()
+	rec, err = recorder.New(path, recorder.ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Mode() != recorder.ModeReplay {
+		t.Fatalf("Mode = %v, want ModeReplay with a cassette", rec.Mode())
+	}
+	replayed := testSession(t, srv.URL, rec)
+	if fmt.Sprint(replayed) != fmt.Sprint(recorded) || recorded[0] == recorded[1] {
+		t.Errorf("replayed hosts %v, recorded %v", replayed, recorded)
+	}
+}
+
+func TestReplayUnknownRequest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	os.WriteFile(path, []byte(`{"interactions":[{"request":{"jsonrpc":"2.0","method":"APIInfo.version","params":{}},"status":200,"response":{"jsonrpc":"2.0","result":"7.0.0"}}]}`), 0o644)
+
+	rec, err := recorder.New(path, recorder.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api, err := zapi.NewAPIWithOptions("http://zabbix.invalid/api_jsonrpc.php", zapi.WithHTTPClient(rec.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := api.ServerVersion.String(); v != "7.0.0" {
+		t.Errorf("ServerVersion = %s, want 7.0.0", v)
+	}
+	if _, err = api.HostsGet(zapi.Params{}); err == nil || !strings.Contains(err.Error(), "no interaction") {
+		t.Errorf("expected a no interaction error, got %v", err)
+	}
+}
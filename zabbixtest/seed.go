@@ -0,0 +1,66 @@
+package zabbixtest
+
+// seed stores the default objects of a fresh Zabbix installation of the server version.
+func (s *Server) seed() {
+	if s.atLeast("6.2") {
+		s.seedObject("templategroup", object{"groupid": "1", "name": "Templates"})
+	} else {
+		s.seedObject("hostgroup", object{"groupid": "1", "name": "Templates"})
+	}
+	s.seedObject("hostgroup", object{"groupid": "2", "name": "Linux servers"})
+	s.seedObject("hostgroup", object{"groupid": "4", "name": "Zabbix servers"})
+	s.seedObject("hostgroup", object{"groupid": "5", "name": "Discovered hosts", "internal": "1"})
+
+	if s.atLeast("5.2") {
+		s.seedObject("role", object{"roleid": "1", "name": "User role", "type": "1"})
+		s.seedObject("role", object{"roleid": "2", "name": "Admin role", "type": "2"})
+		s.seedObject("role", object{"roleid": "3", "name": "Super admin role", "type": "3", "readonly": "1"})
+		s.seedObject("role", object{"roleid": "4", "name": "Guest role", "type": "1"})
+	}
+
+	s.seedObject("usergroup", object{"usrgrpid": "7", "name": "Zabbix administrators"})
+	s.seedObject("usergroup", object{"usrgrpid": "8", "name": "Guests"})
+	s.seedObject("usergroup", object{"usrgrpid": "9", "name": "Disabled", "users_status": "1"})
+	s.seedObject("usergroup", object{"usrgrpid": "11", "name": "Enabled debug mode", "debug_mode": "1"})
+	s.seedObject("usergroup", object{"usrgrpid": "12", "name": "No access to the frontend", "gui_access": "3"})
+	s.seedObject("usergroup", object{"usrgrpid": "13", "name": "Internal", "gui_access": "1"})
+
+	admin := object{
+		"userid": "1", s.userNameField(): AdminUser, "name": "Zabbix", "surname": "Administrator",
+		"passwd": AdminPassword, "usrgrps": []interface{}{object{"usrgrpid": "7"}}, "medias": []interface{}{},
+	}
+	guest := object{
+		"userid": "2", s.userNameField(): "guest", "passwd": "",
+		"usrgrps": []interface{}{object{"usrgrpid": "8"}}, "medias": []interface{}{},
+	}
+	if s.atLeast("5.2") {
+		admin["roleid"], guest["roleid"] = "3", "4"
+	} else {
+		admin["type"], guest["type"] = "3", "1"
+	}
+	s.seedObject("user", admin)
+	s.seedObject("user", guest)
+
+	s.seedObject("mediatype", object{
+		"mediatypeid": "1", "name": "Email", "type": "0",
+		"smtp_server": "mail.example.com", "smtp_helo": "example.com", "smtp_email": "zabbix@example.com",
+	})
+	s.seedObject("mediatype", object{"mediatypeid": "3", "name": "SMS", "type": "2", "gsm_modem": "/dev/ttyS0"})
+	html := object{
+		"mediatypeid": "4", "name": "Email (HTML)", "type": "0",
+		"smtp_server": "mail.example.com", "smtp_helo": "example.com", "smtp_email": "zabbix@example.com",
+	}
+	if s.atLeast("7.0") {
+		html["message_format"] = "1"
+	} else {
+		html["content_type"] = "1"
+	}
+	s.seedObject("mediatype", html)
+}
+
+// seedObject stores o, an object of the API name with its ID, with the defaults of create.
+func (s *Server) seedObject(name string, o object) {
+	k := kinds[name]
+	setDefaults(o, k.defaults)
+	s.insert(k, o)
+}
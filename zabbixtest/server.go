@@ -0,0 +1,420 @@
+// Package zabbixtest runs an in-memory fake of the Zabbix JSON-RPC API, to test code using
+// the API without a Zabbix server:
+//
+//	srv := zabbixtest.NewServer(zabbixtest.WithVersion("6.0.0"))
+//	defer srv.Close()
+//	api, err := zabbix.NewAPI(srv.URL)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	_, err = api.Login(zabbixtest.AdminUser, zabbixtest.AdminPassword)
+//
+// The server implements apiinfo.version, user.login, user.logout, user.checkAuthentication and
+// the get, create, update and delete methods of host groups, hosts, templates, template groups,
+// items, triggers, user macros and actions, as well as LLD rules, item and trigger prototypes,
+// applications, media types, roles, users and user groups. It models the main semantics of
+// Zabbix: generated IDs, required and unique fields with the error codes and messages of Zabbix,
+// references to other objects, cascading deletes, filter, search, sorting, limits, countOutput,
+// preservekeys, select* joins and the APIs of the reported server version.
+//
+// It does not check permissions, every session being a Super admin one, and it returns
+// trigger expressions as they were written instead of with function IDs.
+package zabbixtest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultVersion is the version reported by servers without WithVersion.
+const DefaultVersion = "7.0.0"
+
+// Credentials of the Super admin user of new servers.
+const (
+	AdminUser     = "Admin"
+	AdminPassword = "zabbix"
+)
+
+// Option configures a Server.
+type Option func(s *Server)
+
+// WithVersion Reports version, like "6.0.25", from apiinfo.version.
+// The APIs, login parameters and authentication methods follow this version.
+// An empty version keeps DefaultVersion.
+func WithVersion(version string) Option {
+	return func(s *Server) {
+		if version != "" {
+			s.version = version
+		}
+	}
+}
+
+// Server is a fake Zabbix API server. Its URL is the API endpoint.
+type Server struct {
+	*httptest.Server
+
+	version string
+	release [3]int // version parsed
+
+	mu       sync.Mutex // guards the fields below
+	data     map[string]map[string]object
+	seqs     map[string]int
+	sessions map[string]string // user IDs by session ID
+}
+
+// NewServer Starts a server holding the default objects of a fresh Zabbix installation,
+// like the Admin user, user groups, roles and media types.
+// It must be closed with Close.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		version:  DefaultVersion,
+		data:     map[string]map[string]object{},
+		seqs:     map[string]int{},
+		sessions: map[string]string{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.release = parseVersion(s.version)
+	for name := range kinds {
+		s.data[name] = map[string]object{}
+	}
+	for seq, id := range firstIDs {
+		s.seqs[seq] = id
+	}
+	s.seed()
+
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// Version Returns the reported server version.
+func (s *Server) Version() string {
+	return s.version
+}
+
+// Objects Returns copies of the stored objects of the API named name, like "host", ordered by ID.
+// They hold every field, including the relations returned only by select* parameters.
+func (s *Server) Objects(name string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rows := s.rows(name)
+	res := make([]map[string]interface{}, len(rows))
+	for i, o := range rows {
+		res[i] = deepCopy(o).(object)
+	}
+	return res
+}
+
+// ServeHTTP Answers a JSON-RPC request or batch.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var bearer string
+	if h := r.Header.Get("Authorization"); strings.HasPrefix(h, "Bearer ") {
+		bearer = strings.TrimPrefix(h, "Bearer ")
+	}
+
+	var res interface{}
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		var requests []json.RawMessage
+		switch {
+		case json.Unmarshal(body, &requests) != nil:
+			res = response(nil, nil, errParse())
+		case len(requests) == 0:
+			res = response(nil, nil, errInvalidRequest(`Invalid parameter "/": cannot be empty.`))
+		default:
+			responses := make([]interface{}, len(requests))
+			for i, request := range requests {
+				responses[i] = s.handle(request, bearer)
+			}
+			res = responses
+		}
+	} else {
+		res = s.handle(body, bearer)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+// request is a JSON-RPC request.
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+	Auth    interface{}     `json:"auth"`
+	ID      interface{}     `json:"id"`
+}
+
+// handle answers one JSON-RPC request.
+func (s *Server) handle(body []byte, bearer string) interface{} {
+	var req request
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	if err := d.Decode(&req); err != nil {
+		return response(nil, nil, errParse())
+	}
+	switch {
+	case req.JSONRPC != "2.0":
+		return response(req.ID, nil, errInvalidRequest(`Invalid parameter "/jsonrpc": value must be "2.0".`))
+	case req.Method == "":
+		return response(req.ID, nil, errInvalidRequest(`Invalid parameter "/method": cannot be empty.`))
+	}
+
+	var params interface{}
+	if len(req.Params) > 0 {
+		d = json.NewDecoder(bytes.NewReader(req.Params))
+		d.UseNumber()
+		if err := d.Decode(&params); err != nil {
+			return response(req.ID, nil, errParse())
+		}
+	}
+	switch params.(type) {
+	case nil:
+		params = object{}
+	case object, []interface{}:
+	default:
+		return response(req.ID, nil, errInvalidRequest(`Invalid parameter "/params": an array or object is expected.`))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	result, err := s.call(strings.ToLower(req.Method), params, req.Auth, bearer)
+	return response(req.ID, result, err)
+}
+
+// call runs method for the session given by auth or bearer.
+func (s *Server) call(method string, params, auth interface{}, bearer string) (interface{}, error) {
+	switch method {
+	case "apiinfo.version":
+		if auth != nil {
+			return nil, errInvalidParams(`The "apiinfo.version" method must be called without the "auth" parameter.`)
+		}
+		return s.version, nil
+	case "user.login":
+		return s.login(params)
+	case "user.checkauthentication":
+		return s.checkAuthentication(params)
+	}
+
+	token := s.token(auth, bearer)
+	if _, err := s.session(token); err != nil {
+		return nil, err
+	}
+	if method == "user.logout" {
+		delete(s.sessions, token)
+		return true, nil
+	}
+	api, action, _ := strings.Cut(method, ".")
+	k, ok := kinds[api]
+	if !ok || !s.available(k) {
+		return nil, errMethodNotFound(`Incorrect API "%s".`, api)
+	}
+
+	switch action {
+	case "get":
+		p, ok := params.(object)
+		if !ok {
+			return nil, errInvalidParams(`Invalid parameter "/": an array is not expected.`)
+		}
+		return s.get(k, p)
+	case "create", "update", "delete":
+		// Writes are transactions, like in Zabbix
+		snapshot := s.snapshot()
+		var res interface{}
+		var err error
+		switch action {
+		case "create":
+			res, err = s.create(k, params)
+		case "update":
+			res, err = s.update(k, params)
+		default:
+			res, err = s.delete(k, params)
+		}
+		if err != nil {
+			s.data, s.seqs = snapshot.data, snapshot.seqs
+		}
+		return res, err
+	}
+	return nil, errMethodNotFound(`Incorrect method "%s".`, method)
+}
+
+// login implements user.login, its user name parameters following the server version.
+func (s *Server) login(params interface{}) (interface{}, error) {
+	p, ok := params.(object)
+	if !ok {
+		return nil, errInvalidParams(`Invalid parameter "/": an array is not expected.`)
+	}
+	// "username" replaced "user" in Zabbix 5.4, which was accepted until 6.4
+	accepted := map[string]bool{"password": true, "userData": true}
+	accepted["user"] = !s.atLeast("6.4")
+	accepted["username"] = s.atLeast("5.4")
+	for key := range p {
+		if !accepted[key] {
+			return nil, errInvalidParams(`Invalid parameter "/": unexpected parameter "%s".`, key)
+		}
+	}
+
+	name, password := str(p["user"]), str(p["password"])
+	if n, ok := p["username"]; ok {
+		name = str(n)
+	}
+	for _, u := range s.rows("user") {
+		if str(u[s.userNameField()]) != name || str(u["passwd"]) != password {
+			continue
+		}
+		session := randomHex(16)
+		s.sessions[session] = str(u["userid"])
+		if !truthy(p["userData"]) {
+			return session, nil
+		}
+		data := s.public(kinds["user"], u)
+		data["sessionid"] = session
+		return data, nil
+	}
+	return nil, errApplication("Incorrect user name or password or account is temporarily blocked.")
+}
+
+// checkAuthentication implements user.checkAuthentication, returning the user of a session.
+func (s *Server) checkAuthentication(params interface{}) (interface{}, error) {
+	p, ok := params.(object)
+	if !ok {
+		return nil, errInvalidParams(`Invalid parameter "/": an array is not expected.`)
+	}
+	token := str(p["sessionid"])
+	if t, ok := p["token"]; ok && s.atLeast("6.4") {
+		token = str(t)
+	}
+	userID, err := s.session(token)
+	if err != nil {
+		return nil, err
+	}
+	data := s.public(kinds["user"], s.find("user", userID))
+	data["sessionid"] = token
+	data["userip"] = "127.0.0.1"
+	data["debug_mode"] = "0"
+	data["gui_access"] = "0"
+	return data, nil
+}
+
+// token returns the token given by the "auth" field or the bearer token.
+// The "auth" field was removed in Zabbix 7.2, and bearer tokens are accepted from 6.4.
+func (s *Server) token(auth interface{}, bearer string) string {
+	token := ""
+	if a, ok := auth.(string); ok && !s.atLeast("7.2") {
+		token = a
+	}
+	if bearer != "" && s.atLeast("6.4") {
+		token = bearer
+	}
+	return token
+}
+
+// session returns the user ID of the session token.
+func (s *Server) session(token string) (userID string, err error) {
+	if userID, ok := s.sessions[token]; ok && token != "" {
+		return userID, nil
+	}
+	if s.atLeast("6.0") {
+		return "", errInvalidParams("Not authorized.")
+	}
+	return "", errInvalidParams("Session terminated, re-login, please.")
+}
+
+// userNameField returns the field holding user names, "alias" before Zabbix 5.4.
+func (s *Server) userNameField() string {
+	if s.atLeast("5.4") {
+		return "username"
+	}
+	return "alias"
+}
+
+// atLeast reports whether the server version is version or later.
+func (s *Server) atLeast(version string) bool {
+	v := parseVersion(version)
+	for i := range v {
+		if s.release[i] != v[i] {
+			return s.release[i] > v[i]
+		}
+	}
+	return true
+}
+
+// available reports whether the server version has the API of k.
+func (s *Server) available(k *kind) bool {
+	return (k.since == "" || s.atLeast(k.since)) && (k.until == "" || !s.atLeast(k.until))
+}
+
+func parseVersion(version string) (v [3]int) {
+	for i, part := range strings.SplitN(version, ".", 3) {
+		v[i], _ = strconv.Atoi(part)
+	}
+	return
+}
+
+// rpcError is a JSON-RPC error, with the codes and messages of Zabbix.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("%d %s %s", e.Code, e.Message, e.Data)
+}
+
+func errParse() error {
+	return &rpcError{-32700, "Parse error.", "Invalid JSON. An error occurred on the server while parsing the JSON text."}
+}
+
+func errInvalidRequest(data string) error {
+	return &rpcError{-32600, "Invalid Request.", data}
+}
+
+func errMethodNotFound(format string, args ...interface{}) error {
+	return &rpcError{-32601, "Method not found.", fmt.Sprintf(format, args...)}
+}
+
+func errInvalidParams(format string, args ...interface{}) error {
+	return &rpcError{-32602, "Invalid params.", fmt.Sprintf(format, args...)}
+}
+
+func errApplication(format string, args ...interface{}) error {
+	return &rpcError{-32500, "Application error.", fmt.Sprintf(format, args...)}
+}
+
+// errNoObject is the error of Zabbix for references to missing objects.
+func errNoObject() error {
+	return errApplication("No permissions to referred object or it does not exist!")
+}
+
+// response returns the JSON-RPC response of a call.
+func response(id, result interface{}, err error) interface{} {
+	res := object{"jsonrpc": "2.0", "id": id}
+	if err != nil {
+		res["error"] = err
+	} else {
+		res["result"] = result
+	}
+	return res
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
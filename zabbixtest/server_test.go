@@ -0,0 +1,163 @@
+package zabbixtest_test
+
+import (
+	"errors"
+	"testing"
+
+	zapi "github.com/claranet/go-zabbix-api"
+	"github.com/claranet/go-zabbix-api/zabbixtest"
+)
+
+func testAPI(t *testing.T, opts ...zabbixtest.Option) (*zabbixtest.Server, *zapi.API) {
+	srv := zabbixtest.NewServer(opts...)
+	t.Cleanup(srv.Close)
+	api, err := zapi.NewAPI(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = api.Login(zabbixtest.AdminUser, zabbixtest.AdminPassword); err != nil {
+		t.Fatal(err)
+	}
+	return srv, api
+}
+
+func TestServerVersion(t *testing.T) {
+	// Template groups exist from Zabbix 6.2
+	for v, supported := range map[string]bool{"5.0.30": false, "6.0.25": false, zabbixtest.DefaultVersion: true, "7.2.0": true} {
+		srv, api := testAPI(t, zabbixtest.WithVersion(v))
+		got, err := api.Version()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != v || srv.Version() != v {
+			t.Errorf("Expected version %s, got %s", v, got)
+		}
+
+		_, err = api.TemplateGroupsGet(zapi.Params{})
+		if supported != (err == nil) {
+			t.Errorf("%s: unexpected error of templategroup.get: %v", v, err)
+		}
+		if err != nil && !errors.Is(err, zapi.ErrVersionUnsupported) {
+			t.Errorf("%s: expected ErrVersionUnsupported, got %v", v, err)
+		}
+	}
+}
+
+func TestServerAuth(t *testing.T) {
+	srv := zabbixtest.NewServer()
+	defer srv.Close()
+	api, err := zapi.NewAPI(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = api.HostGroupsGet(zapi.Params{})
+	if !errors.Is(err, zapi.ErrSessionExpired) {
+		t.Errorf("Expected ErrSessionExpired without login, got %v", err)
+	}
+	if _, err = api.Login(zabbixtest.AdminUser, "wrong"); err == nil {
+		t.Error("Expected error for a wrong password")
+	}
+	if _, err = api.Login(zabbixtest.AdminUser, zabbixtest.AdminPassword); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = api.HostGroupsGet(zapi.Params{}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestServerUniqueness(t *testing.T) {
+	srv, api := testAPI(t)
+
+	groups := zapi.HostGroups{{Name: "test group"}}
+	if err := api.HostGroupsCreate(groups); err != nil {
+		t.Fatal(err)
+	}
+	if groups[0].GroupID == "" {
+		t.Fatal("Expected a generated ID")
+	}
+
+	err := api.HostGroupsCreate(zapi.HostGroups{{Name: "test group"}})
+	var e *zapi.Error
+	if !errors.As(err, &e) || e.Code != -32602 || e.Data != `Host group "test group" already exists.` {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if !errors.Is(err, zapi.ErrAlreadyExists) {
+		t.Errorf("Expected ErrAlreadyExists, got %v", err)
+	}
+
+	// Failed writes change nothing
+	if err = api.HostGroupsCreate(zapi.HostGroups{{Name: "other group"}, {Name: "test group"}}); err == nil {
+		t.Fatal("Expected error")
+	}
+	for _, o := range srv.Objects("hostgroup") {
+		if o["name"] == "other group" {
+			t.Error("Host group of a failed create was stored")
+		}
+	}
+
+	err = api.HostGroupsDelete(zapi.HostGroups{{GroupID: "12345"}})
+	if !errors.Is(err, zapi.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestServerGet(t *testing.T) {
+	_, api := testAPI(t)
+
+	groups := zapi.HostGroups{{Name: "test group"}}
+	if err := api.HostGroupsCreate(groups); err != nil {
+		t.Fatal(err)
+	}
+	hosts := zapi.Hosts{
+		{Host: "web-1", GroupIds: zapi.HostGroupIDs{{GroupID: groups[0].GroupID}}},
+		{Host: "web-2", GroupIds: zapi.HostGroupIDs{{GroupID: groups[0].GroupID}}},
+		{Host: "db-1", GroupIds: zapi.HostGroupIDs{{GroupID: groups[0].GroupID}}},
+	}
+	for i := range hosts {
+		hosts[i].Interfaces = zapi.HostInterfaces{{IP: "127.0.0.1", Main: 1, Port: "10050", Type: zapi.Agent, UseIP: 1}}
+	}
+	if err := api.HostsCreate(hosts); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		params zapi.Params
+		want   []string
+	}{
+		{zapi.Params{"filter": zapi.Params{"host": "db-1"}}, []string{"db-1"}},
+		{zapi.Params{"filter": zapi.Params{"host": []string{"web-1", "db-1"}}}, []string{"web-1", "db-1"}},
+		{zapi.Params{"search": zapi.Params{"host": "WEB"}}, []string{"web-1", "web-2"}},
+		{zapi.Params{"search": zapi.Params{"host": "web-*"}, "searchWildcardsEnabled": true}, []string{"web-1", "web-2"}},
+		{zapi.Params{"groupids": groups[0].GroupID, "sortfield": "host", "sortorder": "DESC", "limit": 2}, []string{"web-2", "web-1"}},
+	} {
+		got, err := api.HostsGet(test.params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, h := range got {
+			names = append(names, h.Host)
+		}
+		if len(names) != len(test.want) {
+			t.Errorf("%v: expected %v, got %v", test.params, test.want, names)
+			continue
+		}
+		for i := range names {
+			if names[i] != test.want[i] {
+				t.Errorf("%v: expected %v, got %v", test.params, test.want, names)
+			}
+		}
+	}
+
+	got, err := api.HostsGet(zapi.Params{"hostids": hosts[0].HostID, "selectInterfaces": "extend"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || len(got[0].Interfaces) != 1 || got[0].Interfaces[0].InterfaceID == "" {
+		t.Errorf("Expected the interface of the host, got %#v", got)
+	}
+	if got, _ = api.HostsGet(zapi.Params{"hostids": hosts[0].HostID}); len(got) != 1 || got[0].Interfaces != nil {
+		t.Errorf("Expected no interfaces without selectInterfaces, got %#v", got)
+	}
+}
@@ -0,0 +1,601 @@
+package zabbixtest
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// object is a stored Zabbix object, or a JSON object of params.
+// Scalar fields are stored as strings, like Zabbix returns them.
+type object = map[string]interface{}
+
+// snapshot is a copy of the stored objects, restored when a write fails.
+type snapshot struct {
+	data map[string]map[string]object
+	seqs map[string]int
+}
+
+func (s *Server) snapshot() snapshot {
+	snap := snapshot{data: make(map[string]map[string]object, len(s.data)), seqs: make(map[string]int, len(s.seqs))}
+	for name, rows := range s.data {
+		copied := make(map[string]object, len(rows))
+		for id, o := range rows {
+			copied[id] = deepCopy(o).(object)
+		}
+		snap.data[name] = copied
+	}
+	for seq, id := range s.seqs {
+		snap.seqs[seq] = id
+	}
+	return snap
+}
+
+// nextID returns a new ID of the sequence seq.
+func (s *Server) nextID(seq string) string {
+	s.seqs[seq]++
+	return strconv.Itoa(s.seqs[seq])
+}
+
+// insert stores o, an object with its ID, keeping the sequence of k after it.
+func (s *Server) insert(k *kind, o object) {
+	id := str(o[k.id])
+	s.data[k.name][id] = o
+	if n, err := strconv.Atoi(id); err == nil && n > s.seqs[k.seq] {
+		s.seqs[k.seq] = n
+	}
+}
+
+// find returns the object of the API name with ID id, nil if there is none.
+func (s *Server) find(name, id string) object {
+	return s.data[name][id]
+}
+
+// rows returns the objects of the API name, ordered by ID.
+func (s *Server) rows(name string) []object {
+	rows := make([]object, 0, len(s.data[name]))
+	for _, o := range s.data[name] {
+		rows = append(rows, o)
+	}
+	id := kinds[name].id
+	sort.Slice(rows, func(i, j int) bool {
+		return less(str(rows[i][id]), str(rows[j][id]))
+	})
+	return rows
+}
+
+// where returns the objects of the API name matching match.
+func (s *Server) where(name string, match func(o object) bool) []object {
+	var res []object
+	for _, o := range s.rows(name) {
+		if match(o) {
+			res = append(res, o)
+		}
+	}
+	return res
+}
+
+// public returns a copy of o without the fields of k returned only by select* parameters.
+func (s *Server) public(k *kind, o object) object {
+	res := make(object, len(o))
+	for key, v := range o {
+		res[key] = deepCopy(v)
+	}
+	for _, key := range k.hidden {
+		delete(res, key)
+	}
+	return res
+}
+
+// publics returns the public copies of objects of the API name.
+func (s *Server) publics(name string, objects []object) []object {
+	res := make([]object, len(objects))
+	for i, o := range objects {
+		res[i] = s.public(kinds[name], o)
+	}
+	return res
+}
+
+// get implements the get method of k.
+func (s *Server) get(k *kind, p object) (interface{}, error) {
+	rows := s.rows(k.name)
+	if ids, ok := idSet(p[k.idsParam]); ok {
+		rows = keep(rows, func(o object) bool { return ids[str(o[k.id])] })
+	}
+	for param, link := range k.links {
+		if ids, ok := idSet(p[param]); ok {
+			rows = keep(rows, func(o object) bool { return link(s, o, ids) })
+		}
+	}
+
+	criteria, err := s.criteria(k, p)
+	if err != nil {
+		return nil, err
+	}
+	if len(criteria) > 0 {
+		byAny := truthy(p["searchByAny"])
+		rows = keep(rows, func(o object) bool {
+			for _, match := range criteria {
+				if match(o) == byAny {
+					return byAny
+				}
+			}
+			return !byAny
+		})
+	}
+
+	sortRows(rows, p["sortfield"], p["sortorder"])
+	if limit, err := strconv.Atoi(str(p["limit"])); err == nil && limit >= 0 && limit < len(rows) {
+		rows = rows[:limit]
+	}
+	if truthy(p["countOutput"]) {
+		return strconv.Itoa(len(rows)), nil
+	}
+
+	res := make([]object, len(rows))
+	for i, o := range rows {
+		res[i] = s.output(k, o, p["output"])
+		for param, sel := range k.selects {
+			if v, ok := p[param]; ok && v != nil {
+				res[i][sel.key] = sel.project(s.selected(sel, o), v)
+			}
+		}
+	}
+	if truthy(p["preservekeys"]) {
+		keyed := make(object, len(res))
+		for i, o := range rows {
+			keyed[str(o[k.id])] = res[i]
+		}
+		return keyed, nil
+	}
+	return res, nil
+}
+
+// criteria returns the conditions of the filter and search params.
+// Filters on fields no object has are ignored, like Zabbix ignores unknown fields.
+func (s *Server) criteria(k *kind, p object) (criteria []func(o object) bool, err error) {
+	known := map[string]bool{}
+	for _, o := range s.data[k.name] {
+		for key := range o {
+			known[key] = true
+		}
+	}
+
+	if filter, ok := p["filter"].(object); ok {
+		for field, want := range filter {
+			values, ok := stringSet(want)
+			if !ok || !known[field] {
+				continue
+			}
+			field := field
+			criteria = append(criteria, func(o object) bool { return values[str(o[field])] })
+		}
+	}
+
+	search, ok := p["search"].(object)
+	if !ok {
+		return
+	}
+	wildcards, start, exclude := truthy(p["searchWildcardsEnabled"]), truthy(p["startSearch"]), truthy(p["excludeSearch"])
+	for field, want := range search {
+		patterns, ok := stringSet(want)
+		if !ok || !known[field] {
+			continue
+		}
+		var res []*regexp.Regexp
+		for pattern := range patterns {
+			expr := regexp.QuoteMeta(strings.ToLower(pattern))
+			if wildcards {
+				expr = strings.ReplaceAll(expr, `\*`, ".*")
+			}
+			if start || wildcards {
+				expr = "^" + expr
+			}
+			if wildcards {
+				expr += "$"
+			}
+			re, err := regexp.Compile("(?s)" + expr)
+			if err != nil {
+				return nil, errInvalidParams(`Invalid parameter "/search/%s": %s.`, field, err)
+			}
+			res = append(res, re)
+		}
+		field := field
+		criteria = append(criteria, func(o object) bool {
+			value := strings.ToLower(str(o[field]))
+			for _, re := range res {
+				if re.MatchString(value) {
+					return !exclude
+				}
+			}
+			return exclude
+		})
+	}
+	return
+}
+
+// output returns the fields of o selected by the output param.
+func (s *Server) output(k *kind, o object, output interface{}) object {
+	public := s.public(k, o)
+	switch output := output.(type) {
+	case []interface{}:
+		return project(public, output)
+	case string:
+		if output == "shorten" || output == "refer" {
+			return object{k.id: o[k.id]}
+		}
+	}
+	return public
+}
+
+// selected returns the objects joined by sel to o.
+func (s *Server) selected(sel selection, o object) []object {
+	objects := sel.objects(s, o)
+	if objects == nil {
+		objects = []object{}
+	}
+	return objects
+}
+
+// project returns the objects of a select* param v: "extend", "count" or a list of fields.
+func (sel selection) project(objects []object, v interface{}) interface{} {
+	if str(v) == "count" {
+		return strconv.Itoa(len(objects))
+	}
+	if fields, ok := v.([]interface{}); ok {
+		projected := make([]object, len(objects))
+		for i, o := range objects {
+			projected[i] = project(o, fields)
+		}
+		objects = projected
+	}
+	if !sel.one {
+		return objects
+	}
+	if len(objects) == 0 {
+		return []interface{}{}
+	}
+	return objects[0]
+}
+
+// project returns the fields of o.
+func project(o object, fields []interface{}) object {
+	res := object{}
+	for _, field := range fields {
+		if v, ok := o[str(field)]; ok {
+			res[str(field)] = v
+		}
+	}
+	return res
+}
+
+// sortRows sorts rows by the sortfield and sortorder params, numbers by value.
+func sortRows(rows []object, sortfield, sortorder interface{}) {
+	fields, orders := list(sortfield), list(sortorder)
+	if len(fields) == 0 {
+		return
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		for n, field := range fields {
+			a, b := str(rows[i][str(field)]), str(rows[j][str(field)])
+			if a == b {
+				continue
+			}
+			desc := false
+			if n < len(orders) {
+				desc = strings.EqualFold(str(orders[n]), "DESC")
+			} else if len(orders) == 1 {
+				desc = strings.EqualFold(str(orders[0]), "DESC")
+			}
+			return less(a, b) != desc
+		}
+		return false
+	})
+}
+
+// create implements the create method of k.
+func (s *Server) create(k *kind, params interface{}) (interface{}, error) {
+	inputs, err := objectsParam(params)
+	if err != nil {
+		return nil, err
+	}
+	ids := []interface{}{}
+	for i, input := range inputs {
+		path := "/" + strconv.Itoa(i+1)
+		if _, ok := input[k.id]; ok {
+			return nil, errInvalidParams(`Invalid parameter "%s": unexpected parameter "%s".`, path, k.id)
+		}
+		for _, field := range k.required {
+			if v, ok := input[field]; !ok || v == nil {
+				return nil, errInvalidParams(`Invalid parameter "%s": the parameter "%s" is missing.`, path, field)
+			}
+		}
+
+		o := normalize(input).(object)
+		setDefaults(o, k.defaults)
+		o[k.id] = s.nextID(k.seq)
+		if err := s.write(k, o, nil, path); err != nil {
+			return nil, err
+		}
+		ids = append(ids, o[k.id])
+	}
+	return object{k.resultKey: ids}, nil
+}
+
+// update implements the update method of k.
+func (s *Server) update(k *kind, params interface{}) (interface{}, error) {
+	inputs, err := objectsParam(params)
+	if err != nil {
+		return nil, err
+	}
+	ids := []interface{}{}
+	for i, input := range inputs {
+		path := "/" + strconv.Itoa(i+1)
+		id := str(input[k.id])
+		if id == "" {
+			return nil, errInvalidParams(`Invalid parameter "%s": the parameter "%s" is missing.`, path, k.id)
+		}
+		for _, field := range k.readonly {
+			if _, ok := input[field]; ok {
+				return nil, errInvalidParams(`Invalid parameter "%s": unexpected parameter "%s".`, path, field)
+			}
+		}
+		old := s.find(k.name, id)
+		if old == nil {
+			return nil, errNoObject()
+		}
+
+		o := deepCopy(old).(object)
+		for field, v := range normalize(input).(object) {
+			o[field] = v
+		}
+		if err := s.write(k, o, old, path); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return object{k.resultKey: ids}, nil
+}
+
+// write validates and stores o, old being the stored object on update and nil on create.
+func (s *Server) write(k *kind, o, old object, path string) error {
+	if k.prepare != nil {
+		if err := k.prepare(s, o, old, path); err != nil {
+			return err
+		}
+	}
+	if k.conflict != nil {
+		if err := k.conflict(s, o); err != nil {
+			return err
+		}
+	}
+	s.insert(k, o)
+	if k.saved != nil {
+		return k.saved(s, o, path)
+	}
+	return nil
+}
+
+// delete implements the delete method of k, whose params are IDs.
+func (s *Server) delete(k *kind, params interface{}) (interface{}, error) {
+	values, ok := params.([]interface{})
+	if !ok {
+		return nil, errInvalidParams(`Invalid parameter "/": an array is expected.`)
+	}
+	ids := make([]interface{}, len(values))
+	for i, v := range values {
+		id := str(v)
+		if _, isObject := v.(object); isObject || id == "" {
+			return nil, errInvalidParams(`Invalid parameter "/%d": a number is expected.`, i+1)
+		}
+		if s.find(k.name, id) == nil {
+			return nil, errNoObject()
+		}
+		ids[i] = id
+	}
+	if k.deleting != nil {
+		set := make(map[string]bool, len(ids))
+		for _, id := range ids {
+			set[id.(string)] = true
+		}
+		if err := k.deleting(s, set); err != nil {
+			return nil, err
+		}
+	}
+	for _, id := range ids {
+		s.remove(k.name, id.(string))
+	}
+	return object{k.deleteKey: ids}, nil
+}
+
+// remove deletes an object and the objects depending on it.
+func (s *Server) remove(name, id string) {
+	o := s.find(name, id)
+	if o == nil {
+		return
+	}
+	delete(s.data[name], id)
+	if k := kinds[name]; k.deleted != nil {
+		k.deleted(s, o)
+	}
+}
+
+// objectsParam returns the objects of create and update params, an object or an array of them.
+func objectsParam(params interface{}) ([]object, error) {
+	if o, ok := params.(object); ok {
+		return []object{o}, nil
+	}
+	values, _ := params.([]interface{})
+	objects := make([]object, len(values))
+	for i, v := range values {
+		o, ok := v.(object)
+		if !ok {
+			return nil, errInvalidParams(`Invalid parameter "/%d": an array is expected.`, i+1)
+		}
+		objects[i] = o
+	}
+	return objects, nil
+}
+
+// setDefaults sets the fields of defaults missing in o.
+func setDefaults(o, defaults object) {
+	for field, v := range defaults {
+		if _, ok := o[field]; !ok {
+			o[field] = deepCopy(v)
+		}
+	}
+}
+
+func keep(rows []object, match func(o object) bool) []object {
+	var res []object
+	for _, o := range rows {
+		if match(o) {
+			res = append(res, o)
+		}
+	}
+	return res
+}
+
+// idSet returns the IDs of a param holding one ID or an array of them.
+// It returns false for a missing or null param, which does not filter.
+func idSet(v interface{}) (map[string]bool, bool) {
+	if v == nil {
+		return nil, false
+	}
+	return stringSet(v)
+}
+
+// stringSet returns the strings of a scalar or of an array of them.
+func stringSet(v interface{}) (map[string]bool, bool) {
+	if v == nil {
+		return nil, false
+	}
+	set := map[string]bool{}
+	for _, value := range list(v) {
+		set[str(value)] = true
+	}
+	return set, true
+}
+
+// list returns v, or its elements if it is an array.
+func list(v interface{}) []interface{} {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return v
+	}
+	return []interface{}{v}
+}
+
+// refs returns the values of field in the objects of the array o[key], like the groupids of a host.
+func refs(o object, key, field string) []string {
+	var res []string
+	for _, v := range list(o[key]) {
+		if ref, ok := v.(object); ok {
+			res = append(res, str(ref[field]))
+		}
+	}
+	return res
+}
+
+// hasRef reports whether an object of the array o[key] has its field in ids.
+func hasRef(o object, key, field string, ids map[string]bool) bool {
+	for _, ref := range refs(o, key, field) {
+		if ids[ref] {
+			return true
+		}
+	}
+	return false
+}
+
+// str returns the string of a scalar, as Zabbix returns it.
+func str(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		if v {
+			return "1"
+		}
+		return "0"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	}
+	return ""
+}
+
+// truthy reports whether a flag param is set.
+func truthy(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	}
+	s := str(v)
+	return s != "" && s != "0" && !strings.EqualFold(s, "false")
+}
+
+// less orders IDs and other numbers by value, and strings alphabetically.
+func less(a, b string) bool {
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		return x < y
+	}
+	return a < b
+}
+
+// normalize returns a copy of v with scalars converted to strings, like Zabbix stores them.
+func normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case object:
+		res := make(object, len(v))
+		for key, value := range v {
+			if value != nil {
+				res[key] = normalize(value)
+			}
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, value := range v {
+			res[i] = normalize(value)
+		}
+		return res
+	case nil:
+		return nil
+	}
+	return str(v)
+}
+
+func deepCopy(v interface{}) interface{} {
+	switch v := v.(type) {
+	case object:
+		res := make(object, len(v))
+		for key, value := range v {
+			res[key] = deepCopy(value)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, value := range v {
+			res[i] = deepCopy(value)
+		}
+		return res
+	case []object:
+		res := make([]interface{}, len(v))
+		for i, value := range v {
+			res[i] = deepCopy(value)
+		}
+		return res
+	}
+	return v
+}